@@ -60,3 +60,116 @@ Create the name of the service account to use
 {{- default "default" .Values.serviceAccount.name }}
 {{- end }}
 {{- end }}
+
+{{/*
+IngressClass used by addons Ingress objects, derived from ingress.type.
+Empty when ingress.type is gateway-api (routes are HTTPRoutes instead).
+*/}}
+{{- define "localplane-addons.ingressClassName" -}}
+{{- $type := .Values.ingress.type | default "haproxy" }}
+{{- if eq $type "haproxy" }}haproxy
+{{- else if eq $type "ingress-nginx" }}nginx
+{{- else if eq $type "traefik" }}traefik
+{{- else if ne $type "gateway-api" }}
+{{- fail (printf "unsupported ingress.type %q (supported: haproxy, ingress-nginx, traefik, gateway-api)" $type) }}
+{{- end }}
+{{- end }}
+
+{{/*
+Whether addons should be exposed with Ingress objects ("true") or with
+Gateway API HTTPRoutes ("").
+*/}}
+{{- define "localplane-addons.useIngress" -}}
+{{- if ne (.Values.ingress.type | default "haproxy") "gateway-api" }}true{{ end }}
+{{- end }}
+
+{{/*
+Deploy an addon chart with the configured GitOps engine: an ArgoCD
+Application (default) or a Flux HelmRepository + HelmRelease.
+Expects a dict with: root, name, namespace, repoURL (https:// or oci://
+registry prefix), chart, version, values (YAML string) and optionally
+serverSideApply.
+*/}}
+{{- define "localplane-addons.app" -}}
+{{- $engine := .root.Values.gitops.engine | default "argocd" }}
+{{- $oci := hasPrefix "oci://" .repoURL }}
+{{- if eq $engine "flux" }}
+apiVersion: source.toolkit.fluxcd.io/v1
+kind: HelmRepository
+metadata:
+  name: {{ .name }}
+  namespace: flux-system
+spec:
+  interval: 1h
+  url: {{ .repoURL }}
+  {{- if $oci }}
+  type: oci
+  {{- end }}
+---
+apiVersion: helm.toolkit.fluxcd.io/v2
+kind: HelmRelease
+metadata:
+  name: {{ .name }}
+  namespace: flux-system
+spec:
+  interval: 10m
+  releaseName: {{ .name }}
+  targetNamespace: {{ .namespace }}
+  install:
+    createNamespace: true
+    crds: CreateReplace
+  upgrade:
+    crds: CreateReplace
+  chart:
+    spec:
+      chart: {{ .chart }}
+      version: {{ .version | quote }}
+      sourceRef:
+        kind: HelmRepository
+        name: {{ .name }}
+  {{- with (.values | default "" | fromYaml) }}
+  values:
+    {{- toYaml . | nindent 4 }}
+  {{- end }}
+{{- else if eq $engine "argocd" }}
+apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata:
+  name: {{ .name }}
+  namespace: argocd
+  finalizers:
+  - resources-finalizer.argocd.argoproj.io
+spec:
+  project: default
+  source:
+    {{- if $oci }}
+    repoURL: {{ printf "%s/%s" .repoURL .chart | quote }}
+    path: .
+    {{- else }}
+    repoURL: {{ .repoURL }}
+    chart: {{ .chart }}
+    {{- end }}
+    targetRevision: {{ .version | quote }}
+    {{- with .values }}
+    helm:
+      values: |
+        {{- . | nindent 8 }}
+    {{- else }}
+    helm: {}
+    {{- end }}
+  destination:
+    server: https://kubernetes.default.svc
+    namespace: {{ .namespace }}
+  syncPolicy:
+    automated:
+      prune: true
+      selfHeal: true
+    syncOptions:
+      - CreateNamespace=true
+      {{- if .serverSideApply }}
+      - ServerSideApply=true
+      {{- end }}
+{{- else }}
+{{- fail (printf "unsupported gitops.engine %q (supported: argocd, flux)" $engine) }}
+{{- end }}
+{{- end }}
@@ -0,0 +1,53 @@
+package charts
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// The workspace-template chart of the repository (../../charts) is copied
+// here so it can be embedded: run `go generate ./charts` after changing it.
+//go:generate sh -c "rm -rf workspace-template && cp -R ../../charts/workspace-template workspace-template"
+
+//go:embed all:workspace-template
+var workspaceTemplate embed.FS
+
+// WorkspaceTemplateDir is the directory of the workspace-template chart in
+// the embedded filesystem.
+const WorkspaceTemplateDir = "workspace-template"
+
+// WorkspaceTemplate returns the workspace-template chart built into the
+// binary, rooted at the chart directory.
+func WorkspaceTemplate() fs.FS {
+	sub, err := fs.Sub(workspaceTemplate, WorkspaceTemplateDir)
+	if err != nil {
+		// the directory is embedded at build time
+		panic(err)
+	}
+	return sub
+}
+
+// WriteWorkspaceTemplate writes the embedded workspace-template chart to
+// dest, creating it when missing.
+func WriteWorkspaceTemplate(dest string) error {
+	return fs.WalkDir(WorkspaceTemplate(), ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, filepath.FromSlash(p))
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		data, err := fs.ReadFile(WorkspaceTemplate(), p)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, data, 0o644); err != nil {
+			return fmt.Errorf("writing %s: %w", target, err)
+		}
+		return nil
+	})
+}
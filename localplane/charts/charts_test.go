@@ -0,0 +1,57 @@
+package charts
+
+import (
+	"bytes"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
+	"testing"
+)
+
+// repoWorkspaceTemplate is the chart of the repository that
+// workspace-template is generated from.
+const repoWorkspaceTemplate = "../../charts/workspace-template"
+
+// readFiles returns the contents of the regular files of fsys by path.
+func readFiles(t *testing.T, fsys fs.FS) map[string][]byte {
+	t.Helper()
+	files := map[string][]byte{}
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(fsys, p)
+		files[p] = data
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+// TestWorkspaceTemplateUpToDate fails when the embedded chart drifted from
+// the chart of the repository: run `go generate ./charts`.
+func TestWorkspaceTemplateUpToDate(t *testing.T) {
+	embedded := readFiles(t, WorkspaceTemplate())
+	repo := readFiles(t, os.DirFS(repoWorkspaceTemplate))
+	if len(repo) == 0 {
+		t.Fatalf("no files under %s", repoWorkspaceTemplate)
+	}
+
+	for _, p := range slices.Sorted(maps.Keys(repo)) {
+		data, ok := embedded[p]
+		switch {
+		case !ok:
+			t.Errorf("%s is missing from the embedded chart; run `go generate ./charts`", p)
+		case !bytes.Equal(data, repo[p]):
+			t.Errorf("%s differs from %s/%s; run `go generate ./charts`", p, repoWorkspaceTemplate, p)
+		}
+	}
+	for _, p := range slices.Sorted(maps.Keys(embedded)) {
+		if _, ok := repo[p]; !ok {
+			t.Errorf("%s is embedded but not in %s; run `go generate ./charts`", p, repoWorkspaceTemplate)
+		}
+	}
+}
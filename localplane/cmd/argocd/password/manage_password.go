@@ -0,0 +1,52 @@
+package password
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"localplane/config"
+	argocdsvc "localplane/utils/argocd"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// managePassword prints the stored admin password of the cluster, or
+// replaces it with a new one when --rotate is set.
+func managePassword(cmd *cobra.Command, args []string) {
+	clusterName, _ := cmd.Flags().GetString("cluster-name")
+	if strings.TrimSpace(clusterName) == "" {
+		log.Fatal().Msg("--cluster-name is required")
+	}
+	clusterDir := filepath.Join(config.CliConfig.Directory, "clusters", clusterName)
+
+	current, err := argocdsvc.LoadAdminPassword(clusterDir)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to read argocd admin password")
+	}
+	if current == "" {
+		log.Fatal().Str("cluster", clusterName).Msg("no admin password stored; the cluster was not created with --argocd-secure")
+	}
+
+	rotate, _ := cmd.Flags().GetBool("rotate")
+	if !rotate {
+		fmt.Println(current)
+		return
+	}
+
+	password, err := argocdsvc.GeneratePassword(20)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to generate argocd admin password")
+	}
+	kubeconfigPath := filepath.Join(clusterDir, "kubeconfig")
+	client := argocdsvc.NewClient(kubeconfigPath)
+	if err := client.SetAdminPassword(cmd.Context(), password); err != nil {
+		log.Fatal().Err(err).Msg("failed to rotate argocd admin password")
+	}
+	if err := argocdsvc.SaveAdminPassword(clusterDir, password); err != nil {
+		log.Fatal().Err(err).Msg("argocd admin password rotated in cluster but could not be stored; run rotate again")
+	}
+	log.Info().Str("cluster", clusterName).Msg("rotated argocd admin password")
+	fmt.Println(password)
+}
@@ -0,0 +1,20 @@
+package password
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the argocd password command
+func NewCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "password",
+		Short: "print or rotate the ArgoCD admin password of a cluster created with --argocd-secure",
+		Run:   managePassword,
+	}
+	// flags
+	cmd.Flags().Bool("rotate", false, "generate a new admin password and apply it to the running ArgoCD")
+	// add subcommands here
+	log.Debug().Msg("argocd password command initialized")
+	return cmd
+}
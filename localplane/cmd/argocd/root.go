@@ -0,0 +1,24 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package argocdCmd
+
+import (
+	"localplane/cmd/argocd/password"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the argocd command
+func NewCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "argocd",
+		Short: "manage the ArgoCD instance of a local cluster",
+	}
+
+	cmd.PersistentFlags().String("cluster-name", "", "name of the cluster (directory under CLI config clusters/)")
+
+	// add subcommands here
+	cmd.AddCommand(password.NewCommand())
+	return cmd
+}
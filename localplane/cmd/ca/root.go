@@ -0,0 +1,22 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package caCmd
+
+import (
+	"localplane/cmd/ca/trust"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the ca command
+func NewCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "ca",
+		Short: "manage the workspace certificate authority used for local TLS",
+	}
+
+	// add subcommands here
+	cmd.AddCommand(trust.NewCommand())
+	return cmd
+}
@@ -0,0 +1,18 @@
+package trust
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the ca trust command
+func NewCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "trust",
+		Short: "install the workspace CA into the Linux system trust store",
+		Run:   trustCA,
+	}
+	// add subcommands here
+	log.Debug().Msg("ca trust command initialized")
+	return cmd
+}
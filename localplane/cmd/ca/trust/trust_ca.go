@@ -0,0 +1,21 @@
+package trust
+
+import (
+	"path/filepath"
+
+	"localplane/config"
+	"localplane/utils/ca"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// trustCA installs the CA found under <directory>/ca into the system trust
+// store so browsers and CLI tools accept the cluster certificates.
+func trustCA(cmd *cobra.Command, args []string) {
+	caClient := ca.NewClient(filepath.Join(config.CliConfig.Directory, "ca"))
+	if err := caClient.TrustSystem(cmd.Context()); err != nil {
+		log.Fatal().Err(err).Msg("failed to trust workspace CA")
+	}
+	log.Info().Str("path", caClient.CertPath()).Msg("workspace CA trusted; restart your browser to pick it up")
+}
@@ -0,0 +1,45 @@
+package create
+
+import (
+	"strings"
+
+	"localplane/config"
+	"localplane/pkg/localplane"
+	"localplane/utils/interactive"
+
+	"github.com/manifoldco/promptui"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// askClusterName returns --cluster-name, prompting for it when missing.
+// Without prompts the default cluster name (cluster.name of the config) is
+// used.
+func askClusterName(cmd *cobra.Command) string {
+	clusterName, _ := cmd.Flags().GetString("cluster-name")
+	if strings.TrimSpace(clusterName) != "" {
+		return clusterName
+	}
+	defaultName := config.CliConfig.Cluster.Name
+	if defaultName == "" {
+		defaultName = localplane.DefaultClusterName
+	}
+	if !interactive.Enabled() {
+		log.Info().Str("cluster", defaultName).Msg("no --cluster-name given; using the default cluster name")
+		return defaultName
+	}
+
+	prompt := promptui.Prompt{
+		Label:   "Enter cluster name:",
+		Default: defaultName,
+	}
+	input, err := prompt.Run()
+	if err != nil {
+		log.Debug().Err(err).Msg("prompt cancelled or failed; using default cluster name")
+		return defaultName
+	}
+	if clusterName = strings.TrimSpace(input); clusterName == "" {
+		return defaultName
+	}
+	return clusterName
+}
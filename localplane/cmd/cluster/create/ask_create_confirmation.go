@@ -3,16 +3,19 @@ package create
 import (
 	"fmt"
 
+	"localplane/utils/interactive"
+
 	"github.com/manifoldco/promptui"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
 // askCreateConfirmation prompts the user to confirm cluster creation unless
-// the --yes flag is provided. Returns true to proceed, false to abort.
+// the --yes flag is provided or prompts are disabled (creating is the
+// default answer). Returns true to proceed, false to abort.
 func askCreateConfirmation(cmd *cobra.Command, clusterName string) bool {
 	yes, _ := cmd.Flags().GetBool("yes")
-	if yes {
+	if yes || !interactive.Enabled() {
 		return true
 	}
 
@@ -0,0 +1,86 @@
+package create
+
+import (
+	"strings"
+
+	"localplane/config"
+	"localplane/pkg/localplane"
+	"localplane/utils/clierror"
+	"localplane/utils/gitops"
+	"localplane/utils/retry"
+
+	"github.com/spf13/cobra"
+)
+
+// createOptions builds the library options from the command flags and the
+// CLI config, the flags set on the command line winning over the config
+// defaults. The cluster name, confirmation and reporter are set by the
+// caller.
+func createOptions(cmd *cobra.Command) (localplane.Options, error) {
+	flags := cmd.Flags()
+	cfg := config.CliConfig
+	opts := localplane.Options{
+		Directory:    cfg.Directory,
+		Domain:       cfg.Cluster.Domain,
+		Template:     cfg.Workspace.Template,
+		TemplateRef:  cfg.Workspace.TemplateRef,
+		Repositories: cfg.Repositories,
+		ArgoCD: localplane.ArgoCDOptions{
+			ChartVersion: cfg.ArgoCD.ChartVersion,
+			ChartPath:    cfg.ArgoCD.ChartPath,
+			ValuesFiles:  cfg.ArgoCD.ValuesFiles,
+			Secure:       cfg.ArgoCD.Secure,
+		},
+		SkipLoadBalancer: cfg.Cluster.LoadBalancer == config.LoadBalancerNone,
+		SkipDNS:          cfg.Cluster.DNS == config.DNSNone,
+		MergeKubeconfig:  cfg.Cluster.MergeKubeconfig,
+	}
+	opts.GitOps, _ = flags.GetString("gitops")
+	if disableArgoCD, _ := flags.GetBool("disable-argocd"); disableArgoCD {
+		opts.GitOps = gitops.EngineNone
+	}
+	opts.Branch, _ = flags.GetString("branch")
+	if strings.TrimSpace(opts.Branch) == "" {
+		return opts, clierror.Newf(clierror.ExitUsage, "--branch must not be empty")
+	}
+	if flags.Changed("template") {
+		opts.Template, _ = flags.GetString("template")
+	}
+	if flags.Changed("template-ref") {
+		opts.TemplateRef, _ = flags.GetString("template-ref")
+	}
+	opts.RepoBranches, _ = flags.GetStringToString("repo-branch")
+	if flags.Changed("argocd-chart-version") || opts.ArgoCD.ChartVersion == "" {
+		opts.ArgoCD.ChartVersion, _ = flags.GetString("argocd-chart-version")
+	}
+	if flags.Changed("argocd-chart") {
+		opts.ArgoCD.ChartPath, _ = flags.GetString("argocd-chart")
+	}
+	if flags.Changed("argocd-values") {
+		opts.ArgoCD.ValuesFiles, _ = flags.GetStringArray("argocd-values")
+	}
+	if flags.Changed("argocd-secure") {
+		opts.ArgoCD.Secure, _ = flags.GetBool("argocd-secure")
+	}
+	opts.Flux.ChartVersion, _ = flags.GetString("flux-chart-version")
+	opts.Flux.ChartPath, _ = flags.GetString("flux-chart")
+	opts.Ingress, _ = flags.GetString("ingress")
+	opts.IngressService, _ = flags.GetString("ingress-service")
+	opts.IngressSelector, _ = flags.GetString("ingress-selector")
+	opts.TLS, _ = flags.GetBool("tls")
+	if flags.Changed("start-lb") {
+		startLB, _ := flags.GetBool("start-lb")
+		opts.SkipLoadBalancer = !startLB
+	}
+	opts.LoadBalancerForeground, _ = flags.GetBool("lb-foreground")
+	if flags.Changed("skip-dns") {
+		opts.SkipDNS, _ = flags.GetBool("skip-dns")
+	}
+	if flags.Changed("merge-kubeconfig") {
+		opts.MergeKubeconfig, _ = flags.GetBool("merge-kubeconfig")
+	}
+	opts.CleanupOnFailure, _ = flags.GetBool("cleanup-on-failure")
+	opts.Timeouts = cfg.Timeouts
+	opts.Retry = retry.FromConfig(cfg.Retry)
+	return opts, opts.Validate()
+}
@@ -2,25 +2,51 @@ package create
 
 import (
 	"fmt"
+
+	"localplane/pkg/localplane"
 )
 
-func displayClusterInfo(clusterName, kubeconfigPath, argoCDUrl, headlampUrl, headlampSecret string) {
+// displayClusterInfo prints how to reach the services of the created
+// cluster.
+func displayClusterInfo(info *localplane.CreateResult) {
 	fmt.Println()
 	fmt.Println()
 
-	fmt.Printf("🎉 Cluster '%s' created successfully! 🎉", clusterName)
+	fmt.Printf("🎉 Cluster '%s' created successfully! 🎉", info.ClusterName)
 	fmt.Println()
 	fmt.Println()
 
 	fmt.Printf("Access your cluster services at the following URLs:")
 	fmt.Println()
 
-	fmt.Printf("🗂️ Kubeconfig: %s", kubeconfigPath)
-	fmt.Println()
-	fmt.Printf("🥷🏻 ArgoCD:   http://%s", argoCDUrl)
-	fmt.Println()
-	fmt.Printf("🔍 Headlamp: http://%s", headlampUrl)
-	fmt.Println()
-	fmt.Printf("🔑 Headlamp Token: %s", headlampSecret)
-	fmt.Println()
+	fmt.Printf("🗂️ Kubeconfig: %s", info.KubeconfigPath)
+	fmt.Println()
+	if info.KubeContext != "" {
+		fmt.Printf("☸️  Context: %s (merged into your kubeconfig)", info.KubeContext)
+	} else {
+		fmt.Printf("☸️  Context: run `localplane kubeconfig merge %s`, or `eval \"$(localplane kubeconfig export %s)\"`", info.ClusterName, info.ClusterName)
+	}
+	fmt.Println()
+	if info.ArgoCDURL != "" {
+		fmt.Printf("🥷🏻 ArgoCD:   %s", info.ArgoCDURL)
+		fmt.Println()
+	}
+	if info.ArgoCDAdminPassword != "" {
+		fmt.Printf("🔐 ArgoCD login: admin / %s", info.ArgoCDAdminPassword)
+		fmt.Println()
+	}
+	fmt.Printf("🔍 Headlamp: %s", info.HeadlampURL)
+	fmt.Println()
+	fmt.Printf("🔑 Headlamp Token: %s", info.HeadlampToken)
+	fmt.Println()
+	if info.LoadBalancer.IngressAddress != "" {
+		fmt.Printf("🌐 Ingress: %s", info.LoadBalancer.IngressAddress)
+	} else {
+		fmt.Printf("⚠️  Ingress: no LoadBalancer address found; hostnames won't resolve until DNS points at the ingress")
+	}
+	fmt.Println()
+	if info.CACertPath != "" {
+		fmt.Printf("🔒 CA certificate: %s (trust it with `localplane ca trust`)", info.CACertPath)
+		fmt.Println()
+	}
 }
@@ -1,6 +1,16 @@
 package create
 
 import (
+	"fmt"
+	"strings"
+
+	"localplane/utils/argocd"
+	"localplane/utils/flux"
+	gitutil "localplane/utils/git"
+	"localplane/utils/gitops"
+	"localplane/utils/ingress"
+	"localplane/utils/output"
+
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
@@ -10,13 +20,33 @@ func NewCommand() *cobra.Command {
 	var cmd = &cobra.Command{
 		Use:   "create",
 		Short: "create a local k8s cluster",
-		Run:   createCluster,
+		RunE:  createCluster,
 	}
 	// flags
 	cmd.Flags().BoolP("yes", "y", false, "don't ask for confirmation; assume yes")
-	cmd.Flags().Bool("start-lb", true, "start local load balancer (cloud-provider-kind)")
+	cmd.Flags().Bool("start-lb", true, "start local load balancer (cloud-provider-kind; default: false when cluster.loadBalancer is none)")
 	cmd.Flags().Bool("lb-foreground", false, "run load balancer in foreground (blocking)")
+	cmd.Flags().String("gitops", gitops.DefaultEngine, fmt.Sprintf("GitOps engine reconciling the local-argo repo (%s)", strings.Join(gitops.Engines(), ", ")))
+	cmd.Flags().String("branch", gitutil.DefaultBranch, "branch of the local-argo repo tracked by the GitOps engine (created from the current branch when missing)")
+	cmd.Flags().String("template", "", "source of the workspace chart: builtin, github:<owner>/<repo>[//<path>], a git URL[//<path>], oci://<chart> or a directory (default: workspace.template from the config, else builtin)")
+	cmd.Flags().String("template-ref", "", "ref (or chart version) of the template source; with the builtin source, downloads the localplane GitHub repository chart at this ref")
+	cmd.Flags().StringToString("repo-branch", nil, "branch tracked for a remote repository of the CLI config, as <name>=<branch> (can be repeated)")
 	cmd.Flags().Bool("disable-argocd", false, "don't perform ArgoCD related setup")
+	_ = cmd.Flags().MarkDeprecated("disable-argocd", "use --gitops=none instead")
+	cmd.Flags().String("argocd-chart-version", argocd.DefaultChartVersion, "version of the argo-cd chart to install from the Argo Helm repository")
+	cmd.Flags().String("argocd-chart", "", "install ArgoCD from a local chart .tgz or directory instead of the Argo Helm repository (offline installs)")
+	cmd.Flags().StringArray("argocd-values", nil, "values file merged on top of the built-in ArgoCD values (can be repeated)")
+	cmd.Flags().Bool("argocd-secure", false, "disable anonymous ArgoCD access and protect the admin account with a generated password")
+	cmd.Flags().String("flux-chart-version", flux.DefaultChartVersion, "version of the flux2 chart to install when --gitops=flux")
+	cmd.Flags().String("flux-chart", "", "install Flux from a local chart .tgz or directory (offline installs)")
+	cmd.Flags().String("ingress", ingress.DefaultType, fmt.Sprintf("ingress controller to install (%s)", strings.Join(ingress.Types(), ", ")))
+	cmd.Flags().String("ingress-service", "", "name of the ingress LoadBalancer service, as <name> or <namespace>/<name> (default: discovered from the ingress type)")
+	cmd.Flags().String("ingress-selector", "", "label selector of the ingress LoadBalancer service (default: derived from the ingress type)")
+	cmd.Flags().Bool("skip-dns", false, "don't update the dnsmasq configuration (default: true when cluster.dns is none)")
+	cmd.Flags().Bool("tls", false, "serve localplane hostnames over HTTPS using a workspace CA and cert-manager")
+	cmd.Flags().Bool("merge-kubeconfig", false, "merge the cluster context, localplane-<name>, into your kubeconfig and switch to it (default: cluster.mergeKubeconfig from the config)")
+	cmd.Flags().Bool("cleanup-on-failure", false, "delete the kind cluster, its load balancer and kubeconfig when the creation fails or is interrupted")
+	output.AddFlag(cmd)
 	// add subcommands here
 	log.Debug().Msg("cluster create command initialized")
 	return cmd
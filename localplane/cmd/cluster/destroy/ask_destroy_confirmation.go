@@ -0,0 +1,40 @@
+package destroy
+
+import (
+	"fmt"
+
+	"localplane/utils/clierror"
+	"localplane/utils/interactive"
+
+	"github.com/manifoldco/promptui"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// askDestroyConfirmation prompts the user to confirm the deletion unless the
+// --yes flag is provided. Without prompts, --yes is required. It returns a
+// classified error when the deletion must not proceed.
+func askDestroyConfirmation(cmd *cobra.Command, clusterName string) error {
+	yes, _ := cmd.Flags().GetBool("yes")
+	if yes {
+		return nil
+	}
+	if !interactive.Enabled() {
+		return clierror.Newf(clierror.ExitUsage, "not deleting cluster %s without confirmation; pass --yes in non-interactive mode", clusterName)
+	}
+
+	prompt := promptui.Select{
+		Label: fmt.Sprintf("Are you sure you want to delete kind cluster '%s'?", clusterName),
+		Items: []string{"No", "Yes"},
+		Size:  2,
+	}
+	i, _, err := prompt.Run()
+	if err != nil {
+		return clierror.New(clierror.ExitAborted, fmt.Errorf("confirmation prompt failed: %w", err))
+	}
+	if i != 1 { // user chose "No"
+		log.Info().Str("name", clusterName).Msg("cluster deletion cancelled by user")
+		return clierror.Newf(clierror.ExitAborted, "cluster deletion cancelled")
+	}
+	return nil
+}
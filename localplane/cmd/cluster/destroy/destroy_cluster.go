@@ -1,21 +1,56 @@
 package destroy
 
 import (
-	"fmt"
+	"errors"
+	"os"
 	"strings"
-	"time"
 
-	"localplane/cmd/cluster/shared"
 	"localplane/config"
-	kindsvc "localplane/utils/kind"
+	"localplane/pkg/localplane"
+	"localplane/utils/clierror"
+	"localplane/utils/interactive"
+	"localplane/utils/output"
+	"localplane/utils/progress"
+	"localplane/utils/retry"
 
-	"github.com/manifoldco/promptui"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
-func destroyCluster(cmd *cobra.Command, args []string) {
-	log.Info().Msg("Deleting local k8s cluster...")
+// destroyResult is the result printed with --output json.
+type destroyResult struct {
+	Success bool `json:"success"`
+	*localplane.DestroyResult
+	Error *output.ErrorInfo `json:"error,omitempty"`
+}
+
+// destroyCluster is the main entrypoint invoked by the cobra command. It
+// prints the result as JSON with --output json (even on failure) and returns
+// the classified error the deletion failed with.
+func destroyCluster(cmd *cobra.Command, args []string) error {
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	// JSON output is meant for scripts: never prompt
+	if format == output.JSON {
+		config.CliConfig.NonInteractive = true
+	}
+
+	res, err := runDestroy(cmd)
+	if format == output.JSON {
+		out := destroyResult{Success: err == nil, DestroyResult: res, Error: output.NewErrorInfo(err)}
+		if perr := output.PrintJSON(os.Stdout, out); perr != nil && err == nil {
+			return perr
+		}
+	}
+	return err
+}
+
+// runDestroy selects and confirms the cluster to delete, then deletes it.
+// The result is returned even when nothing was deleted.
+func runDestroy(cmd *cobra.Command) (*localplane.DestroyResult, error) {
+	res := &localplane.DestroyResult{Steps: []output.Step{}}
 	if config.CliConfig.Debug {
 		log.Debug().Bool("debug", true).Msg("debug enabled")
 	}
@@ -23,59 +58,36 @@ func destroyCluster(cmd *cobra.Command, args []string) {
 	clusterName, _ := cmd.Flags().GetString("cluster-name")
 	// if no cluster name provided, list existing kind clusters and ask user to pick one
 	if strings.TrimSpace(clusterName) == "" {
-		sel, err := selectClusterInteractive()
+		if !interactive.Enabled() {
+			return res, clierror.Newf(clierror.ExitUsage, "--cluster-name is required in non-interactive mode")
+		}
+		sel, err := selectClusterInteractive(cmd)
+		if errors.Is(err, errNoClusters) {
+			return res, clierror.New(clierror.ExitNotFound, err)
+		}
 		if err != nil {
-			log.Info().Msg("no kind clusters found")
-			return
+			return res, clierror.New(clierror.ExitAborted, err)
 		}
 		clusterName = sel
 	}
-
-	kindCfg := shared.FindKindConfig(clusterName)
-	if kindCfg == "" {
-		log.Info().Msg("no kind config file found in current directory; proceeding without one")
-	} else {
-		log.Info().Str("path", kindCfg).Msg("found kind config file in current directory")
-	}
+	res.ClusterName = clusterName
 
 	// confirm deletion with the user
-	prompt := promptui.Select{
-		Label: fmt.Sprintf("Are you sure you want to delete kind cluster '%s'?", clusterName),
-		Items: []string{"No", "Yes"},
-		Size:  2,
-	}
-	i, _, err := prompt.Run()
-	if err != nil {
-		log.Error().Err(err).Msg("confirmation prompt failed")
-		return
-	}
-	if i != 1 { // user chose "No"
-		log.Info().Str("name", clusterName).Msg("cluster deletion cancelled by user")
-		return
+	if err := askDestroyConfirmation(cmd, clusterName); err != nil {
+		return res, err
 	}
 
-	// shutdown cluster
-	kindsvcClient := kindsvc.NewClient("")
-	if err := kindsvcClient.Delete(clusterName); err != nil {
-		log.Error().Err(err).Msg("failed deleting kind cluster")
-	} else {
-		log.Info().Str("name", clusterName).Msg("kind cluster deletion invoked")
-	}
-
-	// stop the load balancer provider if running
-	if err := kindsvcClient.StopLoadBalancer(clusterName); err != nil {
-		log.Warn().Err(err).Str("name", clusterName).Msg("failed to stop cloud-provider-kind load balancer (it may not have been running)")
-	} else {
-		log.Info().Str("name", clusterName).Msg("stopped cloud-provider-kind load balancer (if it was running)")
-	}
-
-	// make sure the cluster is stopped/deleted: poll `kind get clusters` briefly
-	const maxAttempts = 6
-	if !waitForClusterStopped(clusterName, maxAttempts, 1*time.Second) {
-		log.Warn().Str("name", clusterName).Msg("cluster still present after deletion attempts; manual cleanup may be needed")
-		return
+	reporter, err := progress.New(config.CliConfig.Progress, os.Stderr)
+	if err != nil {
+		return res, clierror.New(clierror.ExitUsage, err)
 	}
-
-	// cleanup local files
-	cleanup(clusterName)
+	restoreLogs := progress.Use(reporter)
+	defer restoreLogs()
+	return localplane.DestroyCluster(cmd.Context(), localplane.DestroyOptions{
+		Name:      clusterName,
+		Directory: config.CliConfig.Directory,
+		Timeouts:  config.CliConfig.Timeouts,
+		Retry:     retry.FromConfig(config.CliConfig.Retry),
+		Reporter:  reporter,
+	})
 }
@@ -1,6 +1,8 @@
 package destroy
 
 import (
+	"localplane/utils/output"
+
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
@@ -10,8 +12,11 @@ func NewCommand() *cobra.Command {
 	var cmd = &cobra.Command{
 		Use:   "destroy",
 		Short: "destroy a local k8s cluster",
-		Run:   destroyCluster,
+		RunE:  destroyCluster,
 	}
+	// flags
+	cmd.Flags().BoolP("yes", "y", false, "don't ask for confirmation; assume yes")
+	output.AddFlag(cmd)
 	// add subcommands here
 	log.Debug().Msg("cluster destroy command initialized")
 	return cmd
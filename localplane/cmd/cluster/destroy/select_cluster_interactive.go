@@ -1,43 +1,37 @@
 package destroy
 
 import (
+	"errors"
 	"fmt"
-	"os/exec"
 	"strings"
 
+	"localplane/config"
+	"localplane/pkg/localplane"
+
 	"github.com/manifoldco/promptui"
 	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
 )
 
+// errNoClusters is returned by selectClusterInteractive when there is no
+// cluster to select.
+var errNoClusters = errors.New("no kind clusters found")
+
 // selectClusterInteractive lists existing kind clusters and prompts the user
-// to select one. Returns the selected cluster name or an empty string on
-// non-recoverable errors (caller should decide how to proceed).
-func selectClusterInteractive() (string, error) {
-	out, err := exec.Command("kind", "get", "clusters").CombinedOutput()
-	outStr := strings.TrimSpace(string(out))
-	if err != nil || outStr == "" {
-		return "", fmt.Errorf("no kind clusters found")
-	}
-	lines := strings.Split(outStr, "\n")
-	clusters := []string{}
-	for _, l := range lines {
-		l = strings.TrimSpace(l)
-		if l != "" {
-			clusters = append(clusters, l)
-		}
+// to select one. Returns the selected cluster name or an error when there is
+// no cluster or the selection was aborted.
+func selectClusterInteractive(cmd *cobra.Command) (string, error) {
+	found, err := localplane.ListClusters(cmd.Context(), localplane.ListOptions{Directory: config.CliConfig.Directory})
+	if err != nil {
+		log.Debug().Err(err).Msg("failed to list kind clusters")
+		return "", errNoClusters
 	}
-	if len(clusters) == 0 {
-		return "", fmt.Errorf("no kind clusters found")
+	if len(found) == 0 {
+		return "", errNoClusters
 	}
-
-	// Protect against cases where the command returns a single placeholder
-	// line such as "no kind clusters found" — treat that as no clusters.
-	if len(clusters) == 1 {
-		only := strings.TrimSpace(clusters[0])
-		lower := strings.ToLower(only)
-		if only == "" || strings.Contains(lower, "no kind") || strings.Contains(lower, "no clusters") {
-			return "", fmt.Errorf("no kind clusters found")
-		}
+	clusters := make([]string, len(found))
+	for i, c := range found {
+		clusters[i] = c.Name
 	}
 
 	// use promptui to let the user select a cluster
@@ -4,10 +4,14 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package clusterCmd
 
 import (
+	"time"
+
 	"localplane/cmd/cluster/create"
 	"localplane/cmd/cluster/destroy"
+	"localplane/pkg/localplane"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 // NewCommand creates the cluster command
@@ -18,6 +22,20 @@ func NewCommand() *cobra.Command {
 	}
 
 	cmd.PersistentFlags().String("cluster-name", "", "name of the cluster (directory under CLI config clusters/)")
+	// timeouts, bound to the timeouts section of the config
+	timeoutFlags := []struct {
+		flag, key, usage string
+		value            time.Duration
+	}{
+		{"timeout-cluster-ready", "timeouts.clusterReady", "how long to wait for the pods of a new cluster to be healthy", localplane.DefaultTimeouts.ClusterReady},
+		{"timeout-lb-service", "timeouts.loadBalancerService", "how long to wait for the ingress LoadBalancer service to get an address", localplane.DefaultTimeouts.LoadBalancerService},
+		{"timeout-helm", "timeouts.helmInstall", "how long to wait for the GitOps engine Helm release to be ready", localplane.DefaultTimeouts.HelmInstall},
+		{"timeout-cluster-deleted", "timeouts.clusterDeleted", "how long to wait for a deleted cluster to disappear", localplane.DefaultTimeouts.ClusterDeleted},
+	}
+	for _, f := range timeoutFlags {
+		cmd.PersistentFlags().Duration(f.flag, f.value, f.usage)
+		_ = viper.BindPFlag(f.key, cmd.PersistentFlags().Lookup(f.flag))
+	}
 
 	// add subcommands here
 	cmd.AddCommand(create.NewCommand())
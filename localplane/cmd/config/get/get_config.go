@@ -0,0 +1,31 @@
+package get
+
+import (
+	"fmt"
+
+	"localplane/config"
+	"localplane/utils/clierror"
+
+	"github.com/spf13/cobra"
+)
+
+// getConfig prints the effective value of a key: scalars as is, sections
+// and lists as YAML.
+func getConfig(cmd *cobra.Command, args []string) error {
+	value, ok := config.Get(config.ToMap(config.CliConfig), args[0])
+	if !ok {
+		_, err := config.LookupKey(args[0])
+		return clierror.New(clierror.ExitUsage, err)
+	}
+	switch value.(type) {
+	case map[string]any, []any:
+		data, err := config.MarshalYAML(value)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(data))
+	default:
+		fmt.Println(value)
+	}
+	return nil
+}
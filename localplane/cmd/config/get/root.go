@@ -0,0 +1,19 @@
+package get
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the config get command
+func NewCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "get <key>",
+		Short: "print the effective value of a config key or section, e.g. cluster.domain",
+		Args:  cobra.ExactArgs(1),
+		RunE:  getConfig,
+	}
+	// add subcommands here
+	log.Debug().Msg("config get command initialized")
+	return cmd
+}
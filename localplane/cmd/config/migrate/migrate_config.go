@@ -0,0 +1,43 @@
+package migrate
+
+import (
+	"fmt"
+	"os"
+
+	"localplane/config"
+	"localplane/utils/clierror"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// migrateConfig rewrites the config file in the current schema version,
+// saving the original as <file>.bak.
+func migrateConfig(cmd *cobra.Command, args []string) error {
+	path := config.File
+	if len(args) == 1 {
+		path = args[0]
+	}
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return clierror.New(clierror.ExitUsage, fmt.Errorf("reading config file: %w", err))
+	}
+	doc, version, err := config.ReadFile(path)
+	if err != nil {
+		return clierror.New(clierror.ExitUsage, err)
+	}
+	if version == config.CurrentVersion {
+		log.Info().Str("file", path).Int("version", version).Msg("config file already uses the current schema version")
+		return nil
+	}
+
+	backup := path + ".bak"
+	if err := os.WriteFile(backup, original, 0o644); err != nil {
+		return fmt.Errorf("saving a copy of the config file: %w", err)
+	}
+	if err := config.WriteFile(path, doc); err != nil {
+		return err
+	}
+	log.Info().Str("file", path).Str("backup", backup).Int("from", version).Int("to", config.CurrentVersion).Msg("config file migrated")
+	return nil
+}
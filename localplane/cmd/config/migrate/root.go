@@ -0,0 +1,19 @@
+package migrate
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the config migrate command
+func NewCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "migrate [file]",
+		Short: "rewrite a config file of an older schema version in the current one, keeping a .bak copy",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  migrateConfig,
+	}
+	// add subcommands here
+	log.Debug().Msg("config migrate command initialized")
+	return cmd
+}
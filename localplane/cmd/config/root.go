@@ -0,0 +1,33 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package configCmd
+
+import (
+	"localplane/cmd/config/get"
+	"localplane/cmd/config/migrate"
+	"localplane/cmd/config/set"
+	"localplane/cmd/config/validate"
+	"localplane/cmd/config/view"
+	"localplane/config"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the config command
+func NewCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "config",
+		Short: "view, edit and validate the localplane config file",
+		// the subcommands are how an invalid config gets fixed
+		Annotations: map[string]string{config.SkipValidationAnnotation: "true"},
+	}
+
+	// add subcommands here
+	cmd.AddCommand(view.NewCommand())
+	cmd.AddCommand(get.NewCommand())
+	cmd.AddCommand(set.NewCommand())
+	cmd.AddCommand(validate.NewCommand())
+	cmd.AddCommand(migrate.NewCommand())
+	return cmd
+}
@@ -0,0 +1,56 @@
+package set
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"localplane/config"
+)
+
+// parseValue converts s to the type of key. Durations are kept as strings,
+// as written in config files; lists are comma-separated.
+func parseValue(key config.Key, s string) (any, error) {
+	invalid := func(err error) error {
+		return config.FieldError{Key: key.Name, Message: fmt.Sprintf("invalid value %q: %v", s, err)}
+	}
+	switch {
+	case key.Type == reflect.TypeOf(time.Duration(0)):
+		if _, err := time.ParseDuration(s); err != nil {
+			return nil, invalid(err)
+		}
+		return s, nil
+	case key.Type.Kind() == reflect.String:
+		return s, nil
+	case key.Type.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, invalid(err)
+		}
+		return b, nil
+	case key.Type.Kind() == reflect.Int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, invalid(err)
+		}
+		return n, nil
+	case key.Type.Kind() == reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, invalid(err)
+		}
+		return f, nil
+	case key.Type.Kind() == reflect.Slice && key.Type.Elem().Kind() == reflect.String:
+		list := []any{}
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		return list, nil
+	default:
+		return nil, config.FieldError{Key: key.Name, Message: "can't be set from the command line; edit the config file"}
+	}
+}
@@ -0,0 +1,58 @@
+package set
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"localplane/config"
+)
+
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		key     string
+		value   string
+		want    any
+		errText string
+	}{
+		{key: "cluster.domain", value: "dev.test", want: "dev.test"},
+		{key: "cluster.name", value: "", want: ""},
+		{key: "timeouts.clusterReady", value: "10m", want: "10m"},
+		{key: "retry.maxInterval", value: "1m30s", want: "1m30s"},
+		{key: "timeouts.clusterReady", value: "soon", errText: `invalid value "soon"`},
+		{key: "timeouts.clusterReady", value: "10", errText: "missing unit"},
+		{key: "nonInteractive", value: "true", want: true},
+		{key: "cluster.mergeKubeconfig", value: "0", want: false},
+		{key: "nonInteractive", value: "yes", errText: `invalid value "yes"`},
+		{key: "retry.attempts", value: "5", want: 5},
+		{key: "retry.attempts", value: "5.5", errText: `invalid value "5.5"`},
+		{key: "retry.multiplier", value: "1.5", want: 1.5},
+		{key: "retry.multiplier", value: "fast", errText: `invalid value "fast"`},
+		{key: "argocd.valuesFiles", value: "a.yaml, b.yaml,,", want: []any{"a.yaml", "b.yaml"}},
+		{key: "argocd.valuesFiles", value: "", want: []any{}},
+		{key: "workspaces", value: "shop", errText: "can't be set from the command line"},
+		{key: "repositories", value: "platform", errText: "can't be set from the command line"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			key, err := config.LookupKey(tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := parseValue(key, tt.value)
+			if tt.errText != "" {
+				fieldErrs := config.FieldErrors(err)
+				if len(fieldErrs) != 1 || fieldErrs[0].Key != key.Name || !strings.Contains(err.Error(), tt.errText) {
+					t.Fatalf("parseValue() error = %v; want a %s error containing %q", err, key.Name, tt.errText)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseValue() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseValue() = %#v; want %#v", got, tt.want)
+			}
+		})
+	}
+}
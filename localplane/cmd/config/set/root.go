@@ -0,0 +1,21 @@
+package set
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the config set command
+func NewCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "set <key> [value]",
+		Short: "set a key of the config file, e.g. cluster.domain dev.test; lists are comma-separated",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE:  setConfig,
+	}
+	// flags
+	cmd.Flags().Bool("unset", false, "remove the key from the config file so its default applies")
+	// add subcommands here
+	log.Debug().Msg("config set command initialized")
+	return cmd
+}
@@ -0,0 +1,67 @@
+package set
+
+import (
+	"errors"
+	"fmt"
+
+	"localplane/config"
+	"localplane/utils/clierror"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// setConfig sets or removes a key of the config file, migrated to the
+// current schema version. The file is only written when its values stay
+// valid; unknown keys are only reported, and can be removed with --unset.
+func setConfig(cmd *cobra.Command, args []string) error {
+	unset, _ := cmd.Flags().GetBool("unset")
+	if unset != (len(args) == 1) {
+		return clierror.Newf(clierror.ExitUsage, "expected <key> <value>, or <key> with --unset")
+	}
+	key, err := config.LookupKey(args[0])
+	if err != nil && !unset {
+		return clierror.New(clierror.ExitUsage, err)
+	}
+	if err != nil {
+		// unknown keys can still be removed
+		key.Name = args[0]
+	}
+	if key.Name == "version" {
+		return clierror.Newf(clierror.ExitUsage, "version is set by `localplane config migrate`")
+	}
+	if config.File == "" {
+		return clierror.Newf(clierror.ExitUsage, "no config file: pass --config")
+	}
+
+	doc, _, err := config.ReadFile(config.File)
+	if err != nil {
+		return clierror.New(clierror.ExitUsage, err)
+	}
+	if unset {
+		if !config.Unset(doc, key.Name) {
+			log.Info().Str("key", key.Name).Str("file", config.File).Msg("key not set in the config file")
+		}
+	} else {
+		value, err := parseValue(key, args[1])
+		if err != nil {
+			return clierror.New(clierror.ExitUsage, err)
+		}
+		if err := config.Set(doc, key.Name, value); err != nil {
+			return clierror.New(clierror.ExitUsage, err)
+		}
+	}
+
+	cfg, err := config.Decode(doc)
+	if err = errors.Join(err, cfg.Validate()); err != nil {
+		return clierror.New(clierror.ExitUsage, fmt.Errorf("not saving an invalid config file %s:\n%w", config.File, err))
+	}
+	if err := config.UnknownKeys(doc); err != nil {
+		log.Warn().Err(err).Str("file", config.File).Msg("config file has unknown keys; remove them with `localplane config set --unset <key>`")
+	}
+	if err := config.WriteFile(config.File, doc); err != nil {
+		return err
+	}
+	log.Info().Str("key", key.Name).Str("file", config.File).Msg("config file updated")
+	return nil
+}
@@ -0,0 +1,23 @@
+package validate
+
+import (
+	"localplane/utils/output"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the config validate command
+func NewCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "validate [file]",
+		Short: "check a config file against the schema (default: the config file of the CLI)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  validateConfig,
+	}
+	// flags
+	output.AddFlag(cmd)
+	// add subcommands here
+	log.Debug().Msg("config validate command initialized")
+	return cmd
+}
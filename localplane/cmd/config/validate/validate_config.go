@@ -0,0 +1,95 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"localplane/config"
+	"localplane/utils/clierror"
+	"localplane/utils/output"
+	"localplane/utils/remoterepo"
+	"localplane/utils/templatesource"
+
+	"github.com/spf13/cobra"
+)
+
+// validateResult is the result printed with --output json.
+type validateResult struct {
+	File    string `json:"file"`
+	Exists  bool   `json:"exists"`
+	Version int    `json:"version,omitempty"`
+	Valid   bool   `json:"valid"`
+	// Migrate is set when the file uses an older schema version.
+	Migrate bool                `json:"migrate,omitempty"`
+	Errors  []config.FieldError `json:"errors,omitempty"`
+}
+
+// validateConfig checks the keys and values of a config file, its template
+// source and remote repositories, and prints the problems found. It fails
+// with clierror.ExitUsage when the file is invalid.
+func validateConfig(cmd *cobra.Command, args []string) error {
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	res := validateResult{File: config.File}
+	if len(args) == 1 {
+		res.File = args[0]
+	}
+	if _, err := os.Stat(res.File); err == nil {
+		res.Exists = true
+	}
+	res.Errors = config.FieldErrors(check(res.File, &res.Version))
+	res.Valid = len(res.Errors) == 0
+	res.Migrate = res.Exists && res.Version > 0 && res.Version < config.CurrentVersion
+
+	if format == output.JSON {
+		if err := output.PrintJSON(os.Stdout, res); err != nil {
+			return err
+		}
+	} else {
+		printResult(res)
+	}
+	if !res.Valid {
+		return clierror.Newf(clierror.ExitUsage, "%s: %d problem(s) found", res.File, len(res.Errors))
+	}
+	return nil
+}
+
+// check returns the problems of the config file at path joined, setting
+// version to its schema version.
+func check(path string, version *int) error {
+	doc, v, err := config.ReadFile(path)
+	*version = v
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Decode(doc)
+	errs := []error{config.UnknownKeys(doc), err, cfg.Validate()}
+	if _, err := templatesource.Parse(cfg.Workspace.Template); err != nil {
+		errs = append(errs, config.FieldError{Key: "workspace.template", Message: err.Error()})
+	}
+	if _, err := remoterepo.Load(cfg.Repositories, nil); err != nil {
+		errs = append(errs, config.FieldError{Key: "repositories", Message: err.Error()})
+	}
+	return errors.Join(errs...)
+}
+
+// printResult prints res as text.
+func printResult(res validateResult) {
+	switch {
+	case !res.Exists:
+		fmt.Printf("%s: no config file; the defaults apply\n", res.File)
+	case res.Valid:
+		fmt.Printf("%s: valid (schema version %d)\n", res.File, res.Version)
+	default:
+		fmt.Printf("%s: invalid (schema version %d)\n", res.File, res.Version)
+		for _, e := range res.Errors {
+			fmt.Printf("  - %s\n", e.Error())
+		}
+	}
+	if res.Migrate {
+		fmt.Printf("schema version %d is older than %d; run `localplane config migrate` to update the file\n", res.Version, config.CurrentVersion)
+	}
+}
@@ -0,0 +1,23 @@
+package view
+
+import (
+	"localplane/utils/output"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the config view command
+func NewCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "view",
+		Short: "print the effective configuration: defaults, config file, env vars and flags merged",
+		Args:  cobra.NoArgs,
+		RunE:  viewConfig,
+	}
+	// flags
+	output.AddFlag(cmd)
+	// add subcommands here
+	log.Debug().Msg("config view command initialized")
+	return cmd
+}
@@ -0,0 +1,30 @@
+package view
+
+import (
+	"fmt"
+	"os"
+
+	"localplane/config"
+	"localplane/utils/output"
+
+	"github.com/spf13/cobra"
+)
+
+// viewConfig prints the effective configuration as YAML, or as JSON with
+// --output json.
+func viewConfig(cmd *cobra.Command, args []string) error {
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	values := config.ToMap(config.CliConfig)
+	if format == output.JSON {
+		return output.PrintJSON(os.Stdout, values)
+	}
+	data, err := config.MarshalYAML(values)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("# effective configuration (config file: %s)\n%s", config.File, data)
+	return nil
+}
@@ -0,0 +1,24 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package gitopsCmd
+
+import (
+	"localplane/cmd/gitops/watch"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the gitops command
+func NewCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "gitops",
+		Short: "work with the local-argo repo and the GitOps engine of a local cluster",
+	}
+
+	cmd.PersistentFlags().String("cluster-name", "", "name of the cluster (directory under CLI config clusters/)")
+
+	// add subcommands here
+	cmd.AddCommand(watch.NewCommand())
+	return cmd
+}
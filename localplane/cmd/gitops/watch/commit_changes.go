@@ -0,0 +1,71 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	gitutil "localplane/utils/git"
+	"localplane/utils/gitops"
+
+	"github.com/rs/zerolog/log"
+)
+
+// commitChanges commits the pending changes of the repo with a message
+// listing them and, when engine is set, refreshes what they affect. Errors
+// are logged, the watch goes on.
+func commitChanges(ctx context.Context, gitClient *gitutil.Client, engine gitops.Engine) {
+	files, err := gitClient.Status()
+	if err != nil {
+		log.Error().Err(err).Str("path", gitClient.Path).Msg("failed to read local-argo repo status")
+		return
+	}
+	if len(files) == 0 {
+		log.Debug().Msg("nothing to commit (ignored or reverted changes)")
+		return
+	}
+
+	if err := gitClient.CommitAll(commitMessage(files)); err != nil {
+		if errors.Is(err, gitutil.ErrNothingToCommit) {
+			log.Debug().Msg("nothing to commit")
+		} else {
+			log.Error().Err(err).Str("path", gitClient.Path).Msg("failed to commit local-argo changes")
+		}
+		return
+	}
+	paths := make([]string, 0, len(files))
+	for _, f := range files {
+		paths = append(paths, f.Path)
+	}
+	log.Info().Strs("files", paths).Msg("committed local-argo changes")
+
+	if engine == nil {
+		return
+	}
+	refreshed, err := engine.Refresh(ctx, paths)
+	if err != nil {
+		log.Error().Err(err).Str("engine", engine.Name()).Msg("failed to refresh GitOps engine")
+		return
+	}
+	if len(refreshed) == 0 {
+		log.Info().Str("engine", engine.Name()).Msg("no application sourced from the changed paths")
+		return
+	}
+	log.Info().Strs("refreshed", refreshed).Str("engine", engine.Name()).Msg("asked GitOps engine to refresh")
+}
+
+// commitMessage summarizes the changes: the file name for a single change,
+// a count otherwise, followed by the `git status --porcelain` lines.
+func commitMessage(files []gitutil.FileStatus) string {
+	var b strings.Builder
+	if len(files) == 1 {
+		fmt.Fprintf(&b, "Auto-commit %s\n\n", files[0].Path)
+	} else {
+		fmt.Fprintf(&b, "Auto-commit %d changed files\n\n", len(files))
+	}
+	for _, f := range files {
+		fmt.Fprintf(&b, "%s %s\n", f.Code, f.Path)
+	}
+	return b.String()
+}
@@ -0,0 +1,42 @@
+package watch
+
+import (
+	"path/filepath"
+
+	"localplane/config"
+	"localplane/utils/gitops"
+	"localplane/utils/helmvalues"
+	"localplane/utils/retry"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// resolveEngine returns the engine selected with --gitops or, by default,
+// the one recorded in the workspace values by `cluster create`. It returns
+// nil when the cluster has no GitOps engine and fatally exits on unknown
+// engines.
+func resolveEngine(cmd *cobra.Command, repoPath, kubeconfigPath string) gitops.Engine {
+	name, _ := cmd.Flags().GetString("gitops")
+	if name == "" {
+		valuesPath := filepath.Join(repoPath, "charts", "workspace", "values", "localplane-addons.values.yaml")
+		value, found, err := helmvalues.GetString(valuesPath, "localplane-addons.gitops.engine")
+		if err != nil {
+			log.Warn().Err(err).Str("path", valuesPath).Msg("failed to read the GitOps engine from the workspace values")
+		}
+		if found {
+			name = value
+		}
+	}
+
+	engine, err := gitops.New(name, gitops.Options{Kubeconfig: kubeconfigPath, Retry: retry.FromConfig(config.CliConfig.Retry)})
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid gitops engine")
+	}
+	if engine == nil {
+		log.Info().Msg("no GitOps engine; changes are only committed")
+	} else {
+		log.Debug().Str("engine", engine.Name()).Msg("resolved GitOps engine")
+	}
+	return engine
+}
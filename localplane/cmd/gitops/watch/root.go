@@ -0,0 +1,28 @@
+package watch
+
+import (
+	"fmt"
+	"strings"
+
+	"localplane/utils/gitops"
+	"localplane/utils/gitwatch"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the gitops watch command
+func NewCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "watch",
+		Short: "commit changes of the local-argo repo automatically and refresh the GitOps engine",
+		Run:   watchRepo,
+	}
+	// flags
+	cmd.Flags().Duration("debounce", gitwatch.DefaultDebounce, "quiet period after the last change before committing")
+	cmd.Flags().String("gitops", "", fmt.Sprintf("GitOps engine to refresh (%s; default: read from the workspace values)", strings.Join(gitops.Engines(), ", ")))
+	cmd.Flags().Bool("no-refresh", false, "only commit changes, don't ask the GitOps engine to refresh")
+	// add subcommands here
+	log.Debug().Msg("gitops watch command initialized")
+	return cmd
+}
@@ -0,0 +1,58 @@
+package watch
+
+import (
+	"context"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"localplane/config"
+	"localplane/pkg/localplane"
+	gitutil "localplane/utils/git"
+	"localplane/utils/gitops"
+	"localplane/utils/gitwatch"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// watchRepo watches the local-argo repo of the cluster until interrupted,
+// commits every debounced batch of changes and asks the GitOps engine to
+// refresh the objects sourced from the changed paths.
+func watchRepo(cmd *cobra.Command, args []string) {
+	clusterName, _ := cmd.Flags().GetString("cluster-name")
+	if strings.TrimSpace(clusterName) == "" {
+		log.Fatal().Msg("--cluster-name is required")
+	}
+	clusterDir := filepath.Join(config.CliConfig.Directory, "clusters", clusterName)
+	repoPath := localplane.LocalArgoRepoPath(config.CliConfig.Directory, clusterName)
+	gitClient := gitutil.NewClient(repoPath)
+	if !gitClient.IsRepository() {
+		log.Fatal().Str("path", repoPath).Msg("no local-argo repo for this cluster; was it created with --gitops=none?")
+	}
+
+	var engine gitops.Engine
+	if noRefresh, _ := cmd.Flags().GetBool("no-refresh"); !noRefresh {
+		engine = resolveEngine(cmd, repoPath, filepath.Join(clusterDir, "kubeconfig"))
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// commit what was changed while nobody was watching
+	commitChanges(ctx, gitClient, engine)
+
+	debounce, _ := cmd.Flags().GetDuration("debounce")
+	watcher := gitwatch.NewClient(repoPath, debounce)
+	branch, _ := gitClient.CurrentBranch()
+	log.Info().Str("path", repoPath).Str("branch", branch).Dur("debounce", watcher.Debounce).Msg("watching local-argo repo; press Ctrl+C to stop")
+	err := watcher.Watch(ctx, func(ctx context.Context, changed []string) {
+		log.Debug().Strs("paths", changed).Msg("changes settled")
+		commitChanges(ctx, gitClient, engine)
+	})
+	if err != nil {
+		log.Fatal().Err(err).Str("path", repoPath).Msg("failed to watch local-argo repo")
+	}
+	log.Info().Msg("stopped watching local-argo repo")
+}
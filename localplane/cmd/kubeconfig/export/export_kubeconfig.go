@@ -0,0 +1,42 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"localplane/config"
+	"localplane/pkg/localplane"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// exportKubeconfig prints a shell line pointing KUBECONFIG at the kubeconfig
+// of the cluster, or that kubeconfig with --raw.
+func exportKubeconfig(cmd *cobra.Command, args []string) error {
+	clusterName := config.CliConfig.Cluster.Name
+	if len(args) == 1 {
+		clusterName = args[0]
+	}
+	cfg, err := localplane.LoadClusterKubeconfig(config.CliConfig.Directory, clusterName)
+	if err != nil {
+		return err
+	}
+	if raw, _ := cmd.Flags().GetBool("raw"); raw {
+		data, err := clientcmd.Write(*cfg)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	path := localplane.KubeconfigPath(config.CliConfig.Directory, clusterName)
+	fmt.Printf("export KUBECONFIG=%s\n", shellQuote(path))
+	return nil
+}
+
+// shellQuote quotes s for POSIX shells.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
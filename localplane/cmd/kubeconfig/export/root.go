@@ -0,0 +1,21 @@
+package export
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the kubeconfig export command
+func NewCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "export [cluster]",
+		Short: "print `export KUBECONFIG=...` for the cluster (default: cluster.name), for eval \"$(localplane kubeconfig export)\"",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  exportKubeconfig,
+	}
+	// flags
+	cmd.Flags().Bool("raw", false, "print the kubeconfig of the cluster instead")
+	// add subcommands here
+	log.Debug().Msg("kubeconfig export command initialized")
+	return cmd
+}
@@ -0,0 +1,31 @@
+package merge
+
+import (
+	"localplane/config"
+	"localplane/pkg/localplane"
+	"localplane/utils/kubeconfig"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// mergeKubeconfig merges the context of the cluster into the kubeconfig of
+// the user, replacing the one of a previous cluster of the same name.
+func mergeKubeconfig(cmd *cobra.Command, args []string) error {
+	clusterName := config.CliConfig.Cluster.Name
+	if len(args) == 1 {
+		clusterName = args[0]
+	}
+	cfg, err := localplane.LoadClusterKubeconfig(config.CliConfig.Directory, clusterName)
+	if err != nil {
+		return err
+	}
+	path, _ := cmd.Flags().GetString("kubeconfig")
+	keep, _ := cmd.Flags().GetBool("keep-context")
+	client := kubeconfig.NewClient(path)
+	if err := client.Merge(cfg, !keep); err != nil {
+		return err
+	}
+	log.Info().Str("context", cfg.CurrentContext).Str("kubeconfig", client.Filename()).Bool("current", !keep).Msg("merged the cluster context into the kubeconfig")
+	return nil
+}
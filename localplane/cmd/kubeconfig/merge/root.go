@@ -0,0 +1,21 @@
+package merge
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the kubeconfig merge command
+func NewCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "merge [cluster]",
+		Short: "merge the context of the cluster (default: cluster.name), localplane-<name>, into your kubeconfig and switch to it",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  mergeKubeconfig,
+	}
+	// flags
+	cmd.Flags().Bool("keep-context", false, "don't switch to the merged context")
+	// add subcommands here
+	log.Debug().Msg("kubeconfig merge command initialized")
+	return cmd
+}
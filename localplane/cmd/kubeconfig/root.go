@@ -0,0 +1,30 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package kubeconfigCmd
+
+import (
+	"localplane/cmd/kubeconfig/export"
+	"localplane/cmd/kubeconfig/merge"
+	"localplane/cmd/kubeconfig/unmerge"
+	"localplane/cmd/kubeconfig/use"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the kubeconfig command
+func NewCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "kubeconfig",
+		Short: "export the kubeconfig of a local cluster, or merge its localplane-<name> context into yours",
+	}
+
+	cmd.PersistentFlags().String("kubeconfig", "", "kubeconfig to edit (default: the files of $KUBECONFIG, else ~/.kube/config)")
+
+	// add subcommands here
+	cmd.AddCommand(export.NewCommand())
+	cmd.AddCommand(merge.NewCommand())
+	cmd.AddCommand(unmerge.NewCommand())
+	cmd.AddCommand(use.NewCommand())
+	return cmd
+}
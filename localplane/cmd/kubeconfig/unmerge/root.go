@@ -0,0 +1,20 @@
+package unmerge
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the kubeconfig unmerge command
+func NewCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "unmerge [cluster]",
+		Short: "remove the context of the cluster (default: cluster.name), localplane-<name>, from your kubeconfig",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  unmergeKubeconfig,
+	}
+	// flags
+	// add subcommands here
+	log.Debug().Msg("kubeconfig unmerge command initialized")
+	return cmd
+}
@@ -0,0 +1,30 @@
+package unmerge
+
+import (
+	"localplane/config"
+	"localplane/utils/kubeconfig"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// unmergeKubeconfig removes the context, cluster and user of the cluster
+// from the kubeconfig of the user. The cluster may already be deleted.
+func unmergeKubeconfig(cmd *cobra.Command, args []string) error {
+	clusterName := config.CliConfig.Cluster.Name
+	if len(args) == 1 {
+		clusterName = args[0]
+	}
+	path, _ := cmd.Flags().GetString("kubeconfig")
+	client := kubeconfig.NewClient(path)
+	removed, err := client.Unmerge(clusterName)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		log.Info().Str("context", kubeconfig.ContextName(clusterName)).Str("kubeconfig", client.Filename()).Msg("context not in the kubeconfig")
+		return nil
+	}
+	log.Info().Str("context", kubeconfig.ContextName(clusterName)).Str("kubeconfig", client.Filename()).Msg("removed the cluster context from the kubeconfig")
+	return nil
+}
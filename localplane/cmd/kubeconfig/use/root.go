@@ -0,0 +1,20 @@
+package use
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the kubeconfig use command
+func NewCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "use [cluster]",
+		Short: "switch your kubeconfig to the context of the cluster (default: cluster.name), merging it when missing",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  useKubeconfig,
+	}
+	// flags
+	// add subcommands here
+	log.Debug().Msg("kubeconfig use command initialized")
+	return cmd
+}
@@ -0,0 +1,38 @@
+package use
+
+import (
+	"errors"
+
+	"localplane/config"
+	"localplane/pkg/localplane"
+	"localplane/utils/kubeconfig"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// useKubeconfig makes the context of the cluster the current context of the
+// kubeconfig of the user, merging it first when it isn't there.
+func useKubeconfig(cmd *cobra.Command, args []string) error {
+	clusterName := config.CliConfig.Cluster.Name
+	if len(args) == 1 {
+		clusterName = args[0]
+	}
+	path, _ := cmd.Flags().GetString("kubeconfig")
+	client := kubeconfig.NewClient(path)
+	err := client.Use(clusterName)
+	if errors.Is(err, kubeconfig.ErrNotMerged) {
+		cfg, err := localplane.LoadClusterKubeconfig(config.CliConfig.Directory, clusterName)
+		if err != nil {
+			return err
+		}
+		if err := client.Merge(cfg, true); err != nil {
+			return err
+		}
+		log.Info().Str("context", cfg.CurrentContext).Str("kubeconfig", client.Filename()).Msg("merged the cluster context into the kubeconfig")
+	} else if err != nil {
+		return err
+	}
+	log.Info().Str("context", kubeconfig.ContextName(clusterName)).Msg("switched context")
+	return nil
+}
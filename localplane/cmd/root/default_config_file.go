@@ -0,0 +1,35 @@
+package rootCmd
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"localplane/utils/xdg"
+
+	"github.com/rs/zerolog/log"
+)
+
+// defaultConfigFile returns the config file read without --config:
+// config.yaml in the XDG config directory, else the ~/.localplane.yaml of
+// older versions when only that one exists.
+func defaultConfigFile() (string, error) {
+	path, err := xdg.ConfigFile()
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path, nil
+	}
+	legacy := filepath.Join(home, ".localplane.yaml")
+	if _, err := os.Stat(legacy); err == nil {
+		log.Debug().Str("file", legacy).Str("default", path).Msg("using the config file of older versions")
+		return legacy, nil
+	}
+	return path, nil
+}
@@ -0,0 +1,38 @@
+package rootCmd
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+
+	"localplane/config"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+)
+
+// loadConfigFile records the config file in config.File and, when it
+// exists, replaces what viper read with the file migrated to the current
+// schema version. Unknown keys are logged unless quiet.
+func loadConfigFile(quiet bool) error {
+	config.File = viper.ConfigFileUsed()
+	if _, err := os.Stat(config.File); errors.Is(err, fs.ErrNotExist) {
+		// no config file: defaults, env vars and flags apply
+		return nil
+	}
+
+	doc, version, err := config.ReadFile(config.File)
+	if err != nil {
+		return err
+	}
+	if quiet {
+		return viper.MergeConfigMap(doc)
+	}
+	if version < config.CurrentVersion {
+		log.Warn().Str("file", config.File).Int("version", version).Int("current", config.CurrentVersion).Msg("config file uses an older schema version; run `localplane config migrate` to update it")
+	}
+	if err := config.UnknownKeys(doc); err != nil {
+		log.Warn().Err(err).Str("file", config.File).Msg("config file has unknown keys; run `localplane config validate`")
+	}
+	return viper.MergeConfigMap(doc)
+}
@@ -0,0 +1,56 @@
+package rootCmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"localplane/config"
+	"localplane/utils/workspace"
+	"localplane/utils/xdg"
+
+	"github.com/rs/zerolog/log"
+)
+
+// resolveDirectory makes config.CliConfig.Directory absolute. When no
+// --directory, LOCALPLANE_DIRECTORY or `directory` key set it, it is the
+// path of the current workspace, else the workspace containing the working
+// directory, else the XDG data directory. An unknown current workspace fails
+// validation; commands skipping it fall back to the working directory.
+func resolveDirectory() error {
+	dir := config.CliConfig.Directory
+	source := "config"
+	if dir == "" && config.CliConfig.CurrentWorkspace != "" {
+		if ws, ok := config.CliConfig.LookupWorkspace(config.CliConfig.CurrentWorkspace); ok {
+			dir, source = ws.Path, "current-workspace"
+		}
+	}
+	if dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("determining the working directory: %w", err)
+		}
+		root, found, err := workspace.Find(wd)
+		if err != nil {
+			return fmt.Errorf("looking for a %s file: %w", workspace.MarkerFile, err)
+		}
+		if found {
+			dir, source = root, "workspace"
+		} else {
+			if dir, err = xdg.DataDir(); err != nil {
+				return err
+			}
+			source = "xdg"
+			if info, err := os.Stat(filepath.Join(wd, "clusters")); err == nil && info.IsDir() {
+				log.Warn().Str("directory", dir).Msg("the working directory has a clusters/ directory, which older versions used by default; run `localplane workspace init` to keep using it, or pass --directory .")
+			}
+		}
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	config.CliConfig.Directory = dir
+	log.Debug().Str("directory", dir).Str("source", source).Msg("directory resolved")
+	return nil
+}
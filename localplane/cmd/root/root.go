@@ -4,12 +4,26 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package rootCmd
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io/fs"
+	argocdCmd "localplane/cmd/argocd"
+	caCmd "localplane/cmd/ca"
 	clusterCmd "localplane/cmd/cluster"
+	configCmd "localplane/cmd/config"
+	gitopsCmd "localplane/cmd/gitops"
+	kubeconfigCmd "localplane/cmd/kubeconfig"
+	workspaceCmd "localplane/cmd/workspace"
 	"localplane/config"
+	"localplane/utils/clierror"
+	"localplane/utils/progress"
+	"localplane/utils/retry"
 	"localplane/utils/viperutils"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -24,8 +38,11 @@ var rootCmd = &cobra.Command{
 	Use:   "localplane",
 	Short: "a cli tool to run a kubernetes cluster locally for development and testing",
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		return initializeConfig(cmd)
+		return clierror.New(clierror.ExitUsage, initializeConfig(cmd))
 	},
+	// errors are logged by Execute with their class
+	SilenceErrors: true,
+	SilenceUsage:  true,
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	// Run: func(cmd *cobra.Command, args []string) { },
@@ -33,20 +50,50 @@ var rootCmd = &cobra.Command{
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// It exits with the code of the failure class of the returned error (see
+// clierror). Commands run with a context cancelled on SIGINT or SIGTERM; a
+// second signal kills the CLI without waiting for them to stop.
 func Execute() {
-	err := rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		// restoring the default handling lets a second signal kill the CLI
+		<-ctx.Done()
+		stop()
+		log.Warn().Msg("interrupted; stopping (interrupt again to quit now)")
+	}()
+	err := rootCmd.ExecuteContext(ctx)
 	if err != nil {
-		os.Exit(1)
+		log.Error().Err(err).Str("class", clierror.Class(err)).Msg("command failed")
+		os.Exit(clierror.Code(err))
 	}
 }
 
 func init() {
 	// Here you will define your flags and configuration settings.
-	rootCmd.PersistentFlags().StringP("directory", "d", ".", "Directory where configurations and data are stored")
+	rootCmd.PersistentFlags().StringP("directory", "d", "", "Directory where clusters and data are stored (default: the workspace containing the working directory, else $XDG_DATA_HOME/localplane)")
 	viperutils.MapFlagToEnv(rootCmd, "directory", "LOCALPLANE_DIRECTORY", "directory")
-	rootCmd.PersistentFlags().StringVarP(&CfgFile, "config", "c", "", "config file (default is /.localplane.yaml)")
+	rootCmd.PersistentFlags().StringVarP(&CfgFile, "config", "c", "", "config file (default is $XDG_CONFIG_HOME/localplane/config.yaml, else ~/.localplane.yaml when it exists)")
+	rootCmd.PersistentFlags().Bool("non-interactive", false, "never prompt: use defaults or fail (implied when stdin isn't a terminal)")
+	_ = viper.BindPFlag("nonInteractive", rootCmd.PersistentFlags().Lookup("non-interactive"))
+	_ = viper.BindEnv("nonInteractive", "LOCALPLANE_NON_INTERACTIVE")
+	rootCmd.PersistentFlags().Int("retry-attempts", retry.DefaultPolicy.Attempts, "attempts of the kind, kubectl and GitHub calls failing with a transient error")
+	_ = viper.BindPFlag("retry.attempts", rootCmd.PersistentFlags().Lookup("retry-attempts"))
+	// every key of the schema is known, so that its LOCALPLANE_* env var is read
+	for key, value := range config.Defaults() {
+		viper.SetDefault(key, value)
+	}
+	rootCmd.PersistentFlags().String("progress", progress.ModeAuto, fmt.Sprintf("how long-running commands report their steps on stderr (%s)", strings.Join(progress.Modes(), ", ")))
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return clierror.New(clierror.ExitUsage, err)
+	})
 
 	rootCmd.AddCommand(clusterCmd.NewCommand())
+	rootCmd.AddCommand(caCmd.NewCommand())
+	rootCmd.AddCommand(argocdCmd.NewCommand())
+	rootCmd.AddCommand(configCmd.NewCommand())
+	rootCmd.AddCommand(gitopsCmd.NewCommand())
+	rootCmd.AddCommand(kubeconfigCmd.NewCommand())
+	rootCmd.AddCommand(workspaceCmd.NewCommand())
 }
 
 func initializeConfig(cmd *cobra.Command) error {
@@ -78,32 +125,44 @@ func initializeConfig(cmd *cobra.Command) error {
 	}
 
 	// Prefer explicit config file override via --config flag.
-	if CfgFile != "" {
-		viper.SetConfigFile(CfgFile)
-	} else {
-		// Prefer a hidden file named .localplane-config.yaml if present in the directory.
-		viper.AddConfigPath("$HOME")
-		viper.SetConfigName(".localplane")
-		viper.SetConfigType("yaml")
+	cfgFile := CfgFile
+	if cfgFile == "" {
+		var err error
+		if cfgFile, err = defaultConfigFile(); err != nil {
+			return err
+		}
 	}
+	viper.SetConfigFile(cfgFile)
+	viper.SetConfigType("yaml")
 
 	// 3. Read the configuration file.
 	// If a config file is found, read it in. We use a robust error check
 	// to ignore "file not found" errors, but panic on any other error.
+	skipValidation := skipsConfigValidation(cmd)
 	if err := viper.ReadInConfig(); err != nil {
-		// It's okay if the config file doesn't exist.
-		var configFileNotFoundError viper.ConfigFileNotFoundError
-		if !errors.As(err, &configFileNotFoundError) {
+		// It's okay if the default config file doesn't exist, and the
+		// config commands may create the --config file.
+		if !errors.Is(err, fs.ErrNotExist) || (CfgFile != "" && !skipValidation) {
 			return err
 		}
 	}
 
-	// 5. Unmarshal the configuration into the CliConfig struct.
-	err := viper.Unmarshal(&config.CliConfig)
-	if err != nil {
+	// 4. Migrate a config file of an older schema version in memory.
+	if err := loadConfigFile(skipValidation); err != nil {
 		return err
 	}
 
+	// 5. Unmarshal the configuration into the CliConfig struct and validate
+	// it, unless the command is there to fix it.
+	var err error
+	config.CliConfig, err = config.Load(viper.GetViper())
+	if err != nil {
+		if !skipValidation {
+			return fmt.Errorf("invalid configuration (see `localplane config validate`):\n%w", err)
+		}
+		log.Warn().Err(err).Msg("invalid configuration")
+	}
+
 	debug := os.Getenv("LOG_LEVEL")
 
 	zerolog.SetGlobalLevel(zerolog.InfoLevel)
@@ -111,6 +170,11 @@ func initializeConfig(cmd *cobra.Command) error {
 		zerolog.SetGlobalLevel(zerolog.DebugLevel)
 	}
 
+	// 6. Resolve the directory when not set by a flag, env var or the file.
+	if err := resolveDirectory(); err != nil {
+		return err
+	}
+
 	log.Debug().Interface("config", config.CliConfig).Msg("Configuration initialized successfully")
 
 	return nil
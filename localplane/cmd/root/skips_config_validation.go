@@ -0,0 +1,18 @@
+package rootCmd
+
+import (
+	"localplane/config"
+
+	"github.com/spf13/cobra"
+)
+
+// skipsConfigValidation reports whether cmd or one of its parents has the
+// config.SkipValidationAnnotation.
+func skipsConfigValidation(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if _, ok := c.Annotations[config.SkipValidationAnnotation]; ok {
+			return true
+		}
+	}
+	return false
+}
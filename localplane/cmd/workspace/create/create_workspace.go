@@ -0,0 +1,70 @@
+package create
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"localplane/config"
+	"localplane/utils/clierror"
+	"localplane/utils/workspace"
+	"localplane/utils/xdg"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// createWorkspace makes the directory of a new workspace and adds it to the
+// workspaces of the config file, selecting it with --use. A directory made
+// a workspace by this call is reverted when the config file can't be saved.
+func createWorkspace(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	path, _ := cmd.Flags().GetString("path")
+	use, _ := cmd.Flags().GetBool("use")
+	if path == "" {
+		dataDir, err := xdg.DataDir()
+		if err != nil {
+			return err
+		}
+		path = filepath.Join(dataDir, "workspaces", name)
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	entry := config.WorkspaceDirectoryConfig{Name: name, Path: path}
+	if err := (config.Config{Workspaces: []config.WorkspaceDirectoryConfig{entry}}).Validate(); err != nil {
+		return clierror.New(clierror.ExitUsage, err)
+	}
+	if ws, ok := config.CliConfig.LookupWorkspace(name); ok {
+		return clierror.Newf(clierror.ExitUsage, "workspace %s already exists at %s", name, ws.Path)
+	}
+
+	_, statErr := os.Stat(path)
+	existed := statErr == nil
+	err = workspace.Init(path, name)
+	initialized := err == nil
+	if errors.Is(err, workspace.ErrExists) {
+		log.Info().Str("path", path).Msg("registering the existing workspace")
+	} else if err != nil {
+		return clierror.New(clierror.ExitWorkspace, err)
+	}
+
+	err = config.EditFile(config.File, func(doc map[string]any, c config.Config) error {
+		if err := config.SetWorkspaces(doc, append(c.Workspaces, entry)); err != nil {
+			return err
+		}
+		if use {
+			return config.Set(doc, "currentWorkspace", name)
+		}
+		return nil
+	})
+	if err != nil {
+		if initialized {
+			undoInit(path, existed)
+		}
+		return clierror.New(clierror.ExitUsage, err)
+	}
+	log.Info().Str("name", name).Str("path", path).Bool("current", use).Str("file", config.File).Msg("workspace created")
+	return nil
+}
@@ -0,0 +1,103 @@
+package create
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"localplane/config"
+	"localplane/utils/workspace"
+)
+
+// withConfigFile points config.File at a file holding data.
+func withConfigFile(t *testing.T, data string) {
+	t.Helper()
+	oldFile, oldConfig := config.File, config.CliConfig
+	t.Cleanup(func() { config.File, config.CliConfig = oldFile, oldConfig })
+	config.File = filepath.Join(t.TempDir(), "config.yaml")
+	config.CliConfig = config.Config{}
+	if err := os.WriteFile(config.File, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func runCreate(args ...string) error {
+	cmd := NewCommand()
+	cmd.SetArgs(args)
+	cmd.SilenceUsage, cmd.SilenceErrors = true, true
+	return cmd.Execute()
+}
+
+func TestCreateRegisters(t *testing.T) {
+	withConfigFile(t, "version: 2\n")
+	path := filepath.Join(t.TempDir(), "shop")
+
+	if err := runCreate("shop", "--path", path); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(path, workspace.MarkerFile)); err != nil {
+		t.Errorf("no marker: %v", err)
+	}
+	doc, _, err := config.ReadFile(config.File)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := config.Decode(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ws, ok := c.LookupWorkspace("shop"); !ok || ws.Path != path {
+		t.Errorf("workspace shop = %+v, %v; want it at %s", ws, ok, path)
+	}
+}
+
+func TestCreateRevertedOnInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing bool
+	}{
+		{name: "new directory"},
+		{name: "existing directory", existing: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withConfigFile(t, "version: 2\ncluster:\n  dns: bind\n")
+			path := filepath.Join(t.TempDir(), "shop")
+			if tt.existing {
+				if err := os.MkdirAll(path, 0o755); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := runCreate("shop", "--path", path); err == nil {
+				t.Fatal("create succeeded with an invalid config file")
+			}
+			_, err := os.Stat(filepath.Join(path, workspace.MarkerFile))
+			if !os.IsNotExist(err) {
+				t.Errorf("marker left behind: %v", err)
+			}
+			_, err = os.Stat(path)
+			if tt.existing && err != nil {
+				t.Errorf("existing directory removed: %v", err)
+			}
+			if !tt.existing && !os.IsNotExist(err) {
+				t.Errorf("new directory left behind: %v", err)
+			}
+		})
+	}
+}
+
+func TestCreateKeepsExistingWorkspace(t *testing.T) {
+	withConfigFile(t, "version: 2\ncluster:\n  dns: bind\n")
+	path := filepath.Join(t.TempDir(), "shop")
+	if err := workspace.Init(path, "shop"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runCreate("shop", "--path", path); err == nil {
+		t.Fatal("create succeeded with an invalid config file")
+	}
+	if _, err := os.Stat(filepath.Join(path, workspace.MarkerFile)); err != nil {
+		t.Errorf("marker of the existing workspace removed: %v", err)
+	}
+}
@@ -0,0 +1,26 @@
+package create
+
+import (
+	"localplane/config"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the workspace create command
+func NewCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "create <name>",
+		Short: "create a named workspace directory and register it in the config file",
+		Args:  cobra.ExactArgs(1),
+		RunE:  createWorkspace,
+		// a dangling currentWorkspace must not prevent creating one
+		Annotations: map[string]string{config.SkipValidationAnnotation: "true"},
+	}
+	// flags
+	cmd.Flags().String("path", "", "directory of the workspace, created when missing; an existing workspace is registered as is (default: $XDG_DATA_HOME/localplane/workspaces/<name>)")
+	cmd.Flags().Bool("use", false, "select the new workspace (see workspace use)")
+	// add subcommands here
+	log.Debug().Msg("workspace create command initialized")
+	return cmd
+}
@@ -0,0 +1,22 @@
+package create
+
+import (
+	"os"
+	"path/filepath"
+
+	"localplane/utils/workspace"
+
+	"github.com/rs/zerolog/log"
+)
+
+// undoInit reverts workspace.Init on path: its marker is removed, and the
+// directory too when it didn't exist before and is left empty.
+func undoInit(path string, existed bool) {
+	if err := os.Remove(filepath.Join(path, workspace.MarkerFile)); err != nil {
+		log.Warn().Err(err).Str("path", path).Msg("failed to remove the workspace marker")
+		return
+	}
+	if !existed {
+		_ = os.Remove(path)
+	}
+}
@@ -0,0 +1,35 @@
+package initialize
+
+import (
+	"errors"
+	"path/filepath"
+
+	"localplane/utils/workspace"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// initWorkspace writes the workspace marker file into the directory, so
+// that commands run in it or below use it as their --directory.
+func initWorkspace(cmd *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) == 1 {
+		dir = args[0]
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	name, _ := cmd.Flags().GetString("name")
+	err = workspace.Init(dir, name)
+	if errors.Is(err, workspace.ErrExists) {
+		log.Info().Str("directory", dir).Msg("already a localplane workspace")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	log.Info().Str("directory", dir).Str("marker", workspace.MarkerFile).Msg("initialized localplane workspace; commands run in this directory or below now store their clusters here")
+	return nil
+}
@@ -0,0 +1,21 @@
+package initialize
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the workspace init command
+func NewCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "init [dir]",
+		Short: "make a directory (default: the working directory) the localplane workspace of the commands run inside it",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  initWorkspace,
+	}
+	// flags
+	cmd.Flags().String("name", "", "name of the workspace (default: the directory name)")
+	// add subcommands here
+	log.Debug().Msg("workspace init command initialized")
+	return cmd
+}
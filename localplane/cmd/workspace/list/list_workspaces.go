@@ -0,0 +1,75 @@
+package list
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"localplane/config"
+	"localplane/utils/output"
+	"localplane/utils/workspace"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// workspaceInfo is a workspace of the JSON output.
+type workspaceInfo struct {
+	Name     string   `json:"name"`
+	Path     string   `json:"path"`
+	Current  bool     `json:"current"`
+	Clusters []string `json:"clusters"`
+}
+
+// listResult is the JSON output of workspace list.
+type listResult struct {
+	Current string `json:"current"`
+	// Directory is the directory the commands use, which --directory may
+	// set to another one than the current workspace.
+	Directory  string          `json:"directory"`
+	Workspaces []workspaceInfo `json:"workspaces"`
+}
+
+// listWorkspaces prints the workspaces of the config with their clusters.
+func listWorkspaces(cmd *cobra.Command, args []string) error {
+	format, err := output.Format(cmd)
+	if err != nil {
+		return err
+	}
+	res := listResult{Current: config.CliConfig.CurrentWorkspace, Directory: config.CliConfig.Directory, Workspaces: []workspaceInfo{}}
+	for _, ws := range config.CliConfig.Workspaces {
+		clusters, err := workspace.Clusters(ws.Path)
+		if err != nil {
+			log.Warn().Err(err).Str("name", ws.Name).Msg("can't list the clusters of the workspace")
+		}
+		if clusters == nil {
+			clusters = []string{}
+		}
+		res.Workspaces = append(res.Workspaces, workspaceInfo{Name: ws.Name, Path: ws.Path, Current: ws.Name == res.Current, Clusters: clusters})
+	}
+	if format == output.JSON {
+		return output.PrintJSON(os.Stdout, res)
+	}
+
+	if len(res.Workspaces) == 0 {
+		log.Info().Str("directory", res.Directory).Msg("no workspaces; create one with `localplane workspace create <name>`")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "CURRENT\tNAME\tPATH\tCLUSTERS")
+	for _, ws := range res.Workspaces {
+		current := ""
+		if ws.Current {
+			current = "*"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", current, ws.Name, ws.Path, strings.Join(ws.Clusters, ","))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if ws, ok := config.CliConfig.LookupWorkspace(res.Current); ok && ws.Path != res.Directory {
+		log.Warn().Str("directory", res.Directory).Msg("--directory, LOCALPLANE_DIRECTORY or the directory key of the config file override the current workspace")
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package list
+
+import (
+	"localplane/config"
+	"localplane/utils/output"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the workspace list command
+func NewCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "list the named workspaces, marking the selected one",
+		Args:    cobra.NoArgs,
+		RunE:    listWorkspaces,
+		// a dangling currentWorkspace must not prevent listing the others
+		Annotations: map[string]string{config.SkipValidationAnnotation: "true"},
+	}
+	// flags
+	output.AddFlag(cmd)
+	// add subcommands here
+	log.Debug().Msg("workspace list command initialized")
+	return cmd
+}
@@ -0,0 +1,41 @@
+package remove
+
+import (
+	"fmt"
+
+	"localplane/config"
+	"localplane/utils/clierror"
+	"localplane/utils/interactive"
+
+	"github.com/manifoldco/promptui"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// askPurgeConfirmation prompts the user to confirm the removal of the
+// workspace files unless --yes is provided. Without prompts, --yes is
+// required.
+func askPurgeConfirmation(cmd *cobra.Command, ws config.WorkspaceDirectoryConfig) error {
+	yes, _ := cmd.Flags().GetBool("yes")
+	if yes {
+		return nil
+	}
+	if !interactive.Enabled() {
+		return clierror.Newf(clierror.ExitUsage, "not removing workspace %s without confirmation; pass --yes in non-interactive mode", ws.Name)
+	}
+
+	prompt := promptui.Select{
+		Label: fmt.Sprintf("Remove the clusters/, ca/, cache/ and mirrors/ of workspace '%s' in %s?", ws.Name, ws.Path),
+		Items: []string{"No", "Yes"},
+		Size:  2,
+	}
+	i, _, err := prompt.Run()
+	if err != nil {
+		return clierror.New(clierror.ExitAborted, fmt.Errorf("confirmation prompt failed: %w", err))
+	}
+	if i != 1 { // user chose "No"
+		log.Info().Str("name", ws.Name).Msg("workspace deletion cancelled by user")
+		return clierror.Newf(clierror.ExitAborted, "workspace deletion cancelled")
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package remove
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"localplane/config"
+	"localplane/pkg/localplane"
+	"localplane/utils/clierror"
+	"localplane/utils/workspace"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// deleteWorkspace removes a workspace from the config file, clearing the
+// selection when it is the current one. With --purge, its directory is
+// purged too, once no cluster runs from it: the files of the CLI are
+// removed, and the directory only when nothing else is left in it.
+func deleteWorkspace(cmd *cobra.Command, args []string) error {
+	purge, _ := cmd.Flags().GetBool("purge")
+	ws, ok := config.CliConfig.LookupWorkspace(args[0])
+	if !ok {
+		return clierror.Newf(clierror.ExitNotFound, "no workspace named %s", args[0])
+	}
+
+	if purge {
+		if err := checkPurgeable(cmd.Context(), ws); err != nil {
+			return err
+		}
+		if err := askPurgeConfirmation(cmd, ws); err != nil {
+			return err
+		}
+	}
+
+	err := config.EditFile(config.File, func(doc map[string]any, c config.Config) error {
+		workspaces := slices.DeleteFunc(c.Workspaces, func(w config.WorkspaceDirectoryConfig) bool { return w.Name == ws.Name })
+		if err := config.SetWorkspaces(doc, workspaces); err != nil {
+			return err
+		}
+		if c.CurrentWorkspace == ws.Name {
+			config.Unset(doc, "currentWorkspace")
+			log.Info().Str("name", ws.Name).Msg("deleting the current workspace; the selection is cleared")
+		}
+		return nil
+	})
+	if err != nil {
+		return clierror.New(clierror.ExitUsage, err)
+	}
+
+	if !purge {
+		log.Info().Str("name", ws.Name).Str("path", ws.Path).Msg("workspace unregistered; its directory is kept (use --purge to remove it)")
+		return nil
+	}
+	removed, err := workspace.Purge(ws.Path)
+	if err != nil {
+		return clierror.New(clierror.ExitWorkspace, err)
+	}
+	if !removed {
+		log.Info().Str("name", ws.Name).Str("path", ws.Path).Msg("workspace deleted; its directory has files of its own and is kept")
+		return nil
+	}
+	log.Info().Str("name", ws.Name).Str("path", ws.Path).Msg("workspace deleted")
+	return nil
+}
+
+// checkPurgeable refuses to remove a directory that isn't a workspace, or
+// that kind clusters created from it still run from. The clusters/<name>
+// directories of destroyed clusters don't count: Purge removes them.
+func checkPurgeable(ctx context.Context, ws config.WorkspaceDirectoryConfig) error {
+	if _, err := os.Stat(filepath.Join(ws.Path, workspace.MarkerFile)); errors.Is(err, os.ErrNotExist) {
+		return clierror.Newf(clierror.ExitUsage, "not removing %s: it has no %s file; remove it yourself", ws.Path, workspace.MarkerFile)
+	} else if err != nil {
+		return err
+	}
+	clusters, err := localplane.ListClusters(ctx, localplane.ListOptions{Directory: ws.Path})
+	if err != nil {
+		return err
+	}
+	var live []string
+	for _, c := range clusters {
+		if c.Managed {
+			live = append(live, c.Name)
+		}
+	}
+	if len(live) > 0 {
+		return clierror.Newf(clierror.ExitUsage, "workspace %s still has running clusters (%s); destroy them first with `localplane cluster destroy -d %s --cluster-name <name>`", ws.Name, strings.Join(live, ", "), ws.Path)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package remove
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"localplane/config"
+	"localplane/pkg/localplane"
+	"localplane/utils/workspace"
+)
+
+// fakeKind is a kind binary keeping the cluster names in the file named by
+// FAKE_KIND_CLUSTERS.
+const fakeKind = `#!/bin/sh
+case "$1 $2" in
+"get clusters") cat "$FAKE_KIND_CLUSTERS" ;;
+"delete cluster") grep -vx "$4" "$FAKE_KIND_CLUSTERS" > "$FAKE_KIND_CLUSTERS.new"; mv "$FAKE_KIND_CLUSTERS.new" "$FAKE_KIND_CLUSTERS" ;;
+*) exit 1 ;;
+esac
+`
+
+// withFakeKind puts fake kind and docker binaries first in PATH, with the
+// given clusters running.
+func withFakeKind(t *testing.T, clusters ...string) {
+	t.Helper()
+	bin := t.TempDir()
+	for name, script := range map[string]string{"kind": fakeKind, "docker": "#!/bin/sh\n"} {
+		if err := os.WriteFile(filepath.Join(bin, name), []byte(script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	state := filepath.Join(t.TempDir(), "clusters")
+	var data string
+	for _, c := range clusters {
+		data += c + "\n"
+	}
+	if err := os.WriteFile(state, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("FAKE_KIND_CLUSTERS", state)
+}
+
+// withWorkspace registers a workspace named shop, with a clusters/dev
+// directory, in a config file of its own.
+func withWorkspace(t *testing.T) config.WorkspaceDirectoryConfig {
+	t.Helper()
+	ws := config.WorkspaceDirectoryConfig{Name: "shop", Path: filepath.Join(t.TempDir(), "shop")}
+	if err := workspace.Init(ws.Path, ws.Name); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(ws.Path, "clusters", "dev", "local-argo"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	oldFile, oldConfig := config.File, config.CliConfig
+	t.Cleanup(func() { config.File, config.CliConfig = oldFile, oldConfig })
+	config.File = filepath.Join(t.TempDir(), "config.yaml")
+	doc := map[string]any{"version": config.CurrentVersion}
+	if err := config.SetWorkspaces(doc, []config.WorkspaceDirectoryConfig{ws}); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.WriteFile(config.File, doc); err != nil {
+		t.Fatal(err)
+	}
+	c, err := config.Decode(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.CliConfig = c
+	return ws
+}
+
+func runDelete(args ...string) error {
+	cmd := NewCommand()
+	cmd.SetArgs(args)
+	cmd.SilenceUsage, cmd.SilenceErrors = true, true
+	return cmd.Execute()
+}
+
+func TestPurgeRefusesRunningClusters(t *testing.T) {
+	withFakeKind(t, "dev", "other")
+	ws := withWorkspace(t)
+
+	err := runDelete(ws.Name, "--purge", "--yes")
+	if err == nil || !strings.Contains(err.Error(), "running clusters (dev)") {
+		t.Fatalf("delete --purge = %v; want a refusal naming dev only", err)
+	}
+	if _, err := os.Stat(filepath.Join(ws.Path, workspace.MarkerFile)); err != nil {
+		t.Errorf("workspace files removed after a refusal: %v", err)
+	}
+	c, err := config.Decode(mustReadConfig(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.LookupWorkspace(ws.Name); !ok {
+		t.Errorf("workspace unregistered after a refusal")
+	}
+}
+
+func TestPurgeAfterDestroy(t *testing.T) {
+	withFakeKind(t, "dev")
+	ws := withWorkspace(t)
+
+	_, err := localplane.DestroyCluster(context.Background(), localplane.DestroyOptions{
+		Name:           "dev",
+		Directory:      ws.Path,
+		UserKubeconfig: filepath.Join(t.TempDir(), "config"),
+	})
+	if err != nil {
+		t.Fatalf("DestroyCluster: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(ws.Path, "clusters", "dev")); err != nil {
+		t.Fatalf("DestroyCluster removed clusters/dev: %v", err)
+	}
+
+	if err := runDelete(ws.Name, "--purge", "--yes"); err != nil {
+		t.Fatalf("delete --purge after destroy: %v", err)
+	}
+	if _, err := os.Stat(ws.Path); !os.IsNotExist(err) {
+		t.Errorf("workspace directory still exists: %v", err)
+	}
+	c, err := config.Decode(mustReadConfig(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.LookupWorkspace(ws.Name); ok {
+		t.Errorf("workspace still registered in %s", config.File)
+	}
+}
+
+func mustReadConfig(t *testing.T) map[string]any {
+	t.Helper()
+	doc, _, err := config.ReadFile(config.File)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
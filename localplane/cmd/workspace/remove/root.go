@@ -0,0 +1,27 @@
+package remove
+
+import (
+	"localplane/config"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the workspace delete command
+func NewCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:     "delete <name>",
+		Aliases: []string{"rm"},
+		Short:   "unregister a named workspace, and remove its files with --purge",
+		Args:    cobra.ExactArgs(1),
+		RunE:    deleteWorkspace,
+		// this is how a broken workspace entry gets removed
+		Annotations: map[string]string{config.SkipValidationAnnotation: "true"},
+	}
+	// flags
+	cmd.Flags().Bool("purge", false, "also remove the files the CLI wrote in the workspace directory, and the directory once empty (its clusters must be destroyed first)")
+	cmd.Flags().BoolP("yes", "y", false, "don't ask for confirmation before --purge; assume yes")
+	// add subcommands here
+	log.Debug().Msg("workspace delete command initialized")
+	return cmd
+}
@@ -0,0 +1,34 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package workspaceCmd
+
+import (
+	"localplane/cmd/workspace/create"
+	"localplane/cmd/workspace/initialize"
+	"localplane/cmd/workspace/list"
+	"localplane/cmd/workspace/remove"
+	"localplane/cmd/workspace/upgrade"
+	"localplane/cmd/workspace/use"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the workspace command
+func NewCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "workspace",
+		Short: "manage localplane workspaces and the workspace chart of a local cluster's local-argo repo",
+	}
+
+	cmd.PersistentFlags().String("cluster-name", "", "name of the cluster (directory under CLI config clusters/)")
+
+	// add subcommands here
+	cmd.AddCommand(initialize.NewCommand())
+	cmd.AddCommand(list.NewCommand())
+	cmd.AddCommand(use.NewCommand())
+	cmd.AddCommand(create.NewCommand())
+	cmd.AddCommand(remove.NewCommand())
+	cmd.AddCommand(upgrade.NewCommand())
+	return cmd
+}
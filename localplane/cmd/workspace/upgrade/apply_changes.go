@@ -0,0 +1,28 @@
+package upgrade
+
+import (
+	"os"
+	"path/filepath"
+
+	gitutil "localplane/utils/git"
+)
+
+// applyChanges writes the changes into dir, removing deleted files.
+func applyChanges(dir string, changes []gitutil.FileChange) error {
+	for _, ch := range changes {
+		path := filepath.Join(dir, filepath.FromSlash(ch.Path))
+		if ch.To == nil {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(*ch.To), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
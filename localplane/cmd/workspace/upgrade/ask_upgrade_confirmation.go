@@ -0,0 +1,34 @@
+package upgrade
+
+import (
+	"localplane/utils/interactive"
+
+	"github.com/manifoldco/promptui"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// askUpgradeConfirmation prompts the user to apply the changes shown unless
+// the --yes flag is provided. Without prompts, --yes is required. Returns
+// true to proceed, false to abort.
+func askUpgradeConfirmation(cmd *cobra.Command) bool {
+	yes, _ := cmd.Flags().GetBool("yes")
+	if yes {
+		return true
+	}
+	if !interactive.Enabled() {
+		log.Fatal().Msg("not applying the workspace upgrade without confirmation; pass --yes (or --dry-run) in non-interactive mode")
+	}
+
+	prompt := promptui.Select{
+		Label: "Apply these changes to the workspace chart?",
+		Items: []string{"Yes", "No"},
+	}
+
+	i, _, err := prompt.Run()
+	if err != nil || i != 0 {
+		log.Info().Msg("aborting workspace upgrade")
+		return false
+	}
+	return true
+}
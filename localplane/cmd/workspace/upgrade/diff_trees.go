@@ -0,0 +1,40 @@
+package upgrade
+
+import (
+	"sort"
+
+	gitutil "localplane/utils/git"
+	"localplane/utils/merge"
+)
+
+// diffTrees returns the changes turning from into to, sorted by path.
+func diffTrees(from, to merge.Tree) []gitutil.FileChange {
+	paths := map[string]struct{}{}
+	for p := range from {
+		paths[p] = struct{}{}
+	}
+	for p := range to {
+		paths[p] = struct{}{}
+	}
+
+	var changes []gitutil.FileChange
+	for p := range paths {
+		f, inFrom := from[p]
+		t, inTo := to[p]
+		if inFrom == inTo && string(f) == string(t) {
+			continue
+		}
+		ch := gitutil.FileChange{Path: p}
+		if inFrom {
+			s := string(f)
+			ch.From = &s
+		}
+		if inTo {
+			s := string(t)
+			ch.To = &s
+		}
+		changes = append(changes, ch)
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
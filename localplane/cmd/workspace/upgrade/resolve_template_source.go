@@ -0,0 +1,39 @@
+package upgrade
+
+import (
+	"fmt"
+
+	"localplane/config"
+	"localplane/utils/templatesource"
+
+	"github.com/spf13/cobra"
+)
+
+// resolveTemplateSource returns the source to upgrade from: --template, else
+// the source the chart was installed from, else the `workspace.template`
+// config. --template-ref (else the `workspace.templateRef` config of a
+// source coming from the config) overrides its ref or version.
+func resolveTemplateSource(cmd *cobra.Command, installed *templatesource.Installed) (templatesource.Source, error) {
+	spec, ref := config.CliConfig.Workspace.Template, config.CliConfig.Workspace.TemplateRef
+	if installed != nil {
+		spec, ref = installed.Source, ""
+	}
+	if cmd.Flags().Changed("template") {
+		spec, _ = cmd.Flags().GetString("template")
+		ref = ""
+	}
+
+	src, err := templatesource.Parse(spec)
+	if err != nil {
+		return src, err
+	}
+	if cmd.Flags().Changed("template-ref") {
+		ref, _ = cmd.Flags().GetString("template-ref")
+	}
+	if ref != "" {
+		if src, err = src.WithRef(ref); err != nil {
+			return src, fmt.Errorf("--template-ref: %w", err)
+		}
+	}
+	return src, nil
+}
@@ -0,0 +1,23 @@
+package upgrade
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the workspace upgrade command
+func NewCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "upgrade",
+		Short: "merge a newer workspace template into the local-argo repo, keeping local edits",
+		Run:   upgradeTemplate,
+	}
+	// flags
+	cmd.Flags().String("template", "", "template source to upgrade to, in the format of cluster create --template (default: the installed source)")
+	cmd.Flags().String("template-ref", "", "ref (or chart version) of the template source; with the builtin source, the localplane GitHub repository chart at this ref")
+	cmd.Flags().Bool("dry-run", false, "only show the changes the upgrade would make")
+	cmd.Flags().BoolP("yes", "y", false, "don't ask for confirmation; assume yes")
+	// add subcommands here
+	log.Debug().Msg("workspace upgrade command initialized")
+	return cmd
+}
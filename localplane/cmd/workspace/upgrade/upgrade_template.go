@@ -0,0 +1,150 @@
+package upgrade
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"localplane/config"
+	"localplane/pkg/localplane"
+	gitutil "localplane/utils/git"
+	"localplane/utils/merge"
+	"localplane/utils/progress"
+	"localplane/utils/retry"
+	"localplane/utils/templatesource"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// upgradeTemplate fetches the workspace template, merges the changes made
+// to it since the installed version with the local edits of the cluster's
+// workspace chart (three-way), shows the resulting diff and commits it to
+// the local-argo repo.
+func upgradeTemplate(cmd *cobra.Command, args []string) {
+	clusterName, _ := cmd.Flags().GetString("cluster-name")
+	if strings.TrimSpace(clusterName) == "" {
+		log.Fatal().Msg("--cluster-name is required")
+	}
+	base := config.CliConfig.Directory
+	repoPath := localplane.LocalArgoRepoPath(base, clusterName)
+	chartPath := filepath.Join(repoPath, "charts", "workspace")
+	stateDir := localplane.TemplateStatePath(base, clusterName)
+
+	gitClient := gitutil.NewClient(repoPath)
+	if !gitClient.IsRepository() {
+		log.Fatal().Str("path", repoPath).Msg("no local-argo repo for this cluster; was it created with --gitops=none?")
+	}
+	if _, err := os.Stat(chartPath); err != nil {
+		log.Fatal().Err(err).Str("path", chartPath).Msg("no workspace chart in the local-argo repo")
+	}
+	status, err := gitClient.Status()
+	if err != nil {
+		log.Fatal().Err(err).Str("path", repoPath).Msg("failed to read local-argo repo status")
+	}
+	if len(status) > 0 {
+		log.Fatal().Str("path", repoPath).Int("files", len(status)).Msg("the local-argo repo has uncommitted changes; commit or discard them before upgrading")
+	}
+
+	installed, err := templatesource.LoadInstalled(stateDir)
+	if err != nil {
+		log.Fatal().Err(err).Str("path", stateDir).Msg("failed to read the installed workspace template")
+	}
+	if installed == nil {
+		log.Warn().Msg("the installed workspace template wasn't recorded (cluster created by an older localplane); every file changed by the template will conflict with local edits")
+	}
+	src, err := resolveTemplateSource(cmd, installed)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid workspace template source")
+	}
+
+	// fetch the new template next to the state directory
+	if err := os.MkdirAll(stateDir, 0o755); err != nil {
+		log.Fatal().Err(err).Str("path", stateDir).Msg("failed to create template state directory")
+	}
+	tmpDir, err := os.MkdirTemp(stateDir, ".upgrade.*")
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create temporary directory")
+	}
+	defer os.RemoveAll(tmpDir)
+	upstreamPath := filepath.Join(tmpDir, "template")
+	reporter, err := progress.New(config.CliConfig.Progress, os.Stderr)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid progress mode")
+	}
+	restoreLogs := progress.Use(reporter)
+	err = progress.NewSteps(reporter).Run(cmd.Context(), "fetch-template", "Fetching workspace template from "+src.String(), false, func() error {
+		client := templatesource.NewClient(filepath.Join(base, "cache"))
+		client.Retry = retry.FromConfig(config.CliConfig.Retry)
+		return client.Fetch(cmd.Context(), src, upstreamPath)
+	})
+	restoreLogs()
+	if err != nil {
+		log.Fatal().Err(err).Str("source", src.String()).Msg("failed to fetch workspace template")
+	}
+
+	var baseTree merge.Tree
+	if installed != nil {
+		if baseTree, err = merge.ReadTree(templatesource.BaseDir(stateDir)); err != nil {
+			log.Fatal().Err(err).Msg("failed to read the installed workspace template")
+		}
+	}
+	localTree, err := merge.ReadTree(chartPath)
+	if err != nil {
+		log.Fatal().Err(err).Str("path", chartPath).Msg("failed to read the workspace chart")
+	}
+	upstreamTree, err := merge.ReadTree(upstreamPath)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to read the fetched workspace template")
+	}
+
+	merged, conflicts := merge.Trees(baseTree, localTree, upstreamTree)
+	changes := diffTrees(localTree, merged)
+	if len(changes) == 0 {
+		log.Info().Str("source", src.String()).Msg("workspace chart is up to date")
+		if err := templatesource.SaveInstalled(stateDir, src, upstreamPath); err != nil {
+			log.Error().Err(err).Msg("failed to record the installed workspace template")
+		}
+		return
+	}
+
+	// paths in the diff are relative to the repo, like `git diff`
+	repoChanges := make([]gitutil.FileChange, len(changes))
+	for i, ch := range changes {
+		ch.Path = "charts/workspace/" + ch.Path
+		repoChanges[i] = ch
+	}
+	diff, err := gitutil.DiffFiles(repoChanges)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to render the upgrade diff")
+	}
+	fmt.Print(diff)
+	for _, p := range conflicts {
+		log.Warn().Str("file", "charts/workspace/"+p).Msg("conflict between local edits and the template")
+	}
+
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		log.Info().Int("files", len(changes)).Int("conflicts", len(conflicts)).Msg("dry run; nothing changed")
+		return
+	}
+	if !askUpgradeConfirmation(cmd) {
+		return
+	}
+
+	if err := applyChanges(chartPath, changes); err != nil {
+		log.Fatal().Err(err).Str("path", chartPath).Msg("failed to write the upgraded workspace chart")
+	}
+	if err := templatesource.SaveInstalled(stateDir, src, upstreamPath); err != nil {
+		log.Error().Err(err).Msg("failed to record the installed workspace template")
+	}
+
+	if len(conflicts) > 0 {
+		log.Warn().Int("conflicts", len(conflicts)).Str("path", repoPath).Msg("workspace chart upgraded with conflicts; resolve the conflict markers, then commit")
+		return
+	}
+	if err := gitClient.CommitAll(fmt.Sprintf("Upgrade workspace chart from %s", src.String())); err != nil {
+		log.Fatal().Err(err).Str("path", repoPath).Msg("failed to commit the upgraded workspace chart")
+	}
+	log.Info().Str("path", repoPath).Int("files", len(changes)).Msg("workspace chart upgraded and committed")
+}
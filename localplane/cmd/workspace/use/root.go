@@ -0,0 +1,25 @@
+package use
+
+import (
+	"localplane/config"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the workspace use command
+func NewCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "use <name>",
+		Short: "select the workspace the cluster commands operate on unless --directory is given",
+		Args:  cobra.RangeArgs(0, 1),
+		RunE:  useWorkspace,
+		// this is how a dangling currentWorkspace gets fixed
+		Annotations: map[string]string{config.SkipValidationAnnotation: "true"},
+	}
+	// flags
+	cmd.Flags().Bool("none", false, "clear the selection: use the workspace of the working directory, else the XDG data directory")
+	// add subcommands here
+	log.Debug().Msg("workspace use command initialized")
+	return cmd
+}
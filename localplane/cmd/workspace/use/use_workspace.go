@@ -0,0 +1,47 @@
+package use
+
+import (
+	"localplane/config"
+	"localplane/utils/clierror"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// useWorkspace stores the selected workspace as currentWorkspace in the
+// config file, or removes it with --none.
+func useWorkspace(cmd *cobra.Command, args []string) error {
+	none, _ := cmd.Flags().GetBool("none")
+	if none != (len(args) == 0) {
+		return clierror.Newf(clierror.ExitUsage, "expected a workspace name, or --none")
+	}
+
+	var ws config.WorkspaceDirectoryConfig
+	if !none {
+		var ok bool
+		if ws, ok = config.CliConfig.LookupWorkspace(args[0]); !ok {
+			return clierror.Newf(clierror.ExitNotFound, "no workspace named %s; create it with `localplane workspace create %s`", args[0], args[0])
+		}
+	}
+	err := config.EditFile(config.File, func(doc map[string]any, c config.Config) error {
+		if none {
+			config.Unset(doc, "currentWorkspace")
+			return nil
+		}
+		return config.Set(doc, "currentWorkspace", ws.Name)
+	})
+	if err != nil {
+		return clierror.New(clierror.ExitUsage, err)
+	}
+
+	if none {
+		log.Info().Str("file", config.File).Msg("workspace selection cleared")
+	} else {
+		log.Info().Str("name", ws.Name).Str("path", ws.Path).Msg("switched workspace")
+	}
+	if viper.GetString("directory") != "" {
+		log.Warn().Str("directory", viper.GetString("directory")).Msg("--directory, LOCALPLANE_DIRECTORY or the directory key of the config file take precedence over the selected workspace")
+	}
+	return nil
+}
@@ -1,11 +1,143 @@
 package config
 
+import "time"
+
 // Config holds CLI configuration that can be populated via env / unmarshal.
 // Fields must be exported (capitalized) so reflection-based unmarshalers can set them.
+// Its mapstructure tags are the keys of the config file, whose layout is
+// versioned by Version (see Migrate).
 type Config struct {
-	Debug     bool   `mapstructure:"debug" json:"debug"`
+	// Version is the schema version of the config file (CurrentVersion once
+	// migrated).
+	Version int  `mapstructure:"version" json:"version"`
+	Debug   bool `mapstructure:"debug" json:"debug"`
+	// Directory holds the clusters, CA and caches of the CLI. Empty until
+	// resolved to the path of CurrentWorkspace, else the workspace containing
+	// the working directory, else the XDG data directory.
 	Directory string `mapstructure:"directory" json:"directory"`
+	// Workspaces are the named workspace directories managed by `localplane
+	// workspace`; CurrentWorkspace is the name of the selected one.
+	Workspaces       []WorkspaceDirectoryConfig `mapstructure:"workspaces" json:"workspaces"`
+	CurrentWorkspace string                     `mapstructure:"currentWorkspace" json:"currentWorkspace"`
+	// NonInteractive disables every prompt (see interactive.Enabled).
+	NonInteractive bool `mapstructure:"nonInteractive" json:"nonInteractive"`
+	// Progress is how long-running commands render their steps (see
+	// progress.New).
+	Progress string `mapstructure:"progress" json:"progress"`
+	// Cluster holds the defaults of `cluster create`.
+	Cluster ClusterConfig `mapstructure:"cluster" json:"cluster"`
+	// ArgoCD holds the defaults of the ArgoCD install.
+	ArgoCD ArgoCDConfig `mapstructure:"argocd" json:"argocd"`
+	// Workspace holds the source of the workspace chart.
+	Workspace WorkspaceConfig `mapstructure:"workspace" json:"workspace"`
+	// Repositories are remote git repositories registered with the GitOps
+	// engine of the clusters created by the CLI.
+	Repositories []RepositoryConfig `mapstructure:"repositories" json:"repositories"`
+	// Timeouts bound the waits of the cluster commands; zero fields keep
+	// their default.
+	Timeouts TimeoutsConfig `mapstructure:"timeouts" json:"timeouts"`
+	// Retry is the backoff of the kind, kubectl and GitHub calls failing
+	// with a transient error (see retry.Policy).
+	Retry RetryConfig `mapstructure:"retry" json:"retry"`
+}
+
+// ClusterConfig holds the defaults of the clusters created by the CLI.
+type ClusterConfig struct {
+	// Name is the cluster name used when --cluster-name is omitted.
+	Name string `mapstructure:"name" json:"name"`
+	// Domain is the DNS domain of the hostnames served by the clusters.
+	Domain string `mapstructure:"domain" json:"domain"`
+	// LoadBalancer is the LoadBalancer provider (LoadBalancerProviders).
+	LoadBalancer string `mapstructure:"loadBalancer" json:"loadBalancer"`
+	// DNS is the backend resolving Domain to the ingress (DNSBackends).
+	DNS string `mapstructure:"dns" json:"dns"`
+	// MergeKubeconfig merges the context of new clusters into the kubeconfig
+	// of the user.
+	MergeKubeconfig bool `mapstructure:"mergeKubeconfig" json:"mergeKubeconfig"`
+}
+
+// ArgoCDConfig holds the defaults of the ArgoCD flags of `cluster create`.
+type ArgoCDConfig struct {
+	// ChartVersion of the argo-cd chart (the pinned version when empty);
+	// ChartPath installs a local chart instead.
+	ChartVersion string `mapstructure:"chartVersion" json:"chartVersion"`
+	ChartPath    string `mapstructure:"chartPath" json:"chartPath"`
+	// ValuesFiles are merged on top of the built-in values.
+	ValuesFiles []string `mapstructure:"valuesFiles" json:"valuesFiles"`
+	// Secure disables anonymous access.
+	Secure bool `mapstructure:"secure" json:"secure"`
+}
+
+// WorkspaceConfig holds the source of the workspace chart written into new
+// local-argo repos.
+type WorkspaceConfig struct {
+	// Template is the source of the chart (see templatesource.Parse); the
+	// built-in chart when empty. TemplateRef overrides its ref or version.
+	Template    string `mapstructure:"template" json:"template"`
+	TemplateRef string `mapstructure:"templateRef" json:"templateRef"`
+}
+
+// WorkspaceDirectoryConfig is a named workspace directory.
+type WorkspaceDirectoryConfig struct {
+	Name string `mapstructure:"name" json:"name"`
+	// Path is the absolute path of the directory.
+	Path string `mapstructure:"path" json:"path"`
+}
+
+// TimeoutsConfig holds the timeouts of the steps waiting on the cluster.
+type TimeoutsConfig struct {
+	// ClusterReady bounds the wait for the pods of a new cluster.
+	ClusterReady time.Duration `mapstructure:"clusterReady" json:"clusterReady"`
+	// LoadBalancerService bounds the wait for the ingress LoadBalancer
+	// service to get an address.
+	LoadBalancerService time.Duration `mapstructure:"loadBalancerService" json:"loadBalancerService"`
+	// HelmInstall bounds the wait for a Helm release (the GitOps engine) to
+	// be ready.
+	HelmInstall time.Duration `mapstructure:"helmInstall" json:"helmInstall"`
+	// ClusterDeleted bounds the wait for a deleted cluster to disappear
+	// from `kind get clusters`.
+	ClusterDeleted time.Duration `mapstructure:"clusterDeleted" json:"clusterDeleted"`
+}
+
+// RetryConfig is an exponential backoff: the first retry waits
+// InitialInterval, each next one Multiplier times longer, up to MaxInterval,
+// for at most Attempts attempts.
+type RetryConfig struct {
+	Attempts        int           `mapstructure:"attempts" json:"attempts"`
+	InitialInterval time.Duration `mapstructure:"initialInterval" json:"initialInterval"`
+	MaxInterval     time.Duration `mapstructure:"maxInterval" json:"maxInterval"`
+	Multiplier      float64       `mapstructure:"multiplier" json:"multiplier"`
+}
+
+// RepositoryConfig describes a remote git repository. Credentials are never
+// stored in the config: they are read from the environment variables named
+// by UsernameEnv / PasswordEnv (default `LOCALPLANE_REPO_<NAME>_USERNAME` and
+// `_PASSWORD`) or from CredentialsFile.
+type RepositoryConfig struct {
+	Name   string `mapstructure:"name" json:"name"`
+	URL    string `mapstructure:"url" json:"url"`
+	Branch string `mapstructure:"branch" json:"branch"`
+	// Path, when set, is deployed from Branch into Namespace (default Name).
+	Path      string `mapstructure:"path" json:"path"`
+	Namespace string `mapstructure:"namespace" json:"namespace"`
+
+	UsernameEnv     string `mapstructure:"usernameEnv" json:"usernameEnv"`
+	PasswordEnv     string `mapstructure:"passwordEnv" json:"passwordEnv"`
+	CredentialsFile string `mapstructure:"credentialsFile" json:"credentialsFile"`
+
+	// Mirror keeps a local mirror under `<directory>/mirrors/` that the
+	// cluster reads instead of the remote, so it keeps working offline.
+	Mirror bool `mapstructure:"mirror" json:"mirror"`
 }
 
 // CliConfig is the package-level configuration instance used by the CLI.
 var CliConfig Config
+
+// File is the config file of the CLI: --config, else config.yaml of the XDG
+// config directory (or the ~/.localplane.yaml of older versions), which may
+// not exist. Set when the configuration is loaded.
+var File string
+
+// SkipValidationAnnotation marks the commands, and their subcommands, that
+// run with an invalid configuration so that they can fix it.
+const SkipValidationAnnotation = "localplane/skip-config-validation"
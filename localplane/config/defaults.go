@@ -0,0 +1,62 @@
+package config
+
+import (
+	"time"
+
+	"localplane/utils/progress"
+)
+
+// Defaults of the cluster section.
+const (
+	DefaultClusterName = "localplane"
+	DefaultDomain      = "localplane"
+)
+
+// LoadBalancer providers of the cluster section.
+const (
+	LoadBalancerCloudProviderKind = "cloud-provider-kind"
+	LoadBalancerNone              = "none"
+)
+
+// DNS backends of the cluster section.
+const (
+	DNSDnsmasq = "dnsmasq"
+	DNSNone    = "none"
+)
+
+// LoadBalancerProviders returns the supported values of cluster.loadBalancer.
+func LoadBalancerProviders() []string {
+	return []string{LoadBalancerCloudProviderKind, LoadBalancerNone}
+}
+
+// DNSBackends returns the supported values of cluster.dns.
+func DNSBackends() []string {
+	return []string{DNSDnsmasq, DNSNone}
+}
+
+// Default returns the configuration of the keys missing from the config
+// file, the environment and the flags.
+func Default() Config {
+	return Config{
+		Version:  CurrentVersion,
+		Progress: progress.ModeAuto,
+		Cluster: ClusterConfig{
+			Name:         DefaultClusterName,
+			Domain:       DefaultDomain,
+			LoadBalancer: LoadBalancerCloudProviderKind,
+			DNS:          DNSDnsmasq,
+		},
+		Timeouts: TimeoutsConfig{
+			ClusterReady:        3 * time.Minute,
+			LoadBalancerService: 3 * time.Minute,
+			HelmInstall:         5 * time.Minute,
+			ClusterDeleted:      30 * time.Second,
+		},
+		Retry: RetryConfig{
+			Attempts:        4,
+			InitialInterval: time.Second,
+			MaxInterval:     10 * time.Second,
+			Multiplier:      2,
+		},
+	}
+}
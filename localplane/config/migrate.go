@@ -0,0 +1,160 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// CurrentVersion is the schema version of the config files written by the
+// CLI. Files without a version key are version 1.
+const CurrentVersion = 2
+
+// migrations upgrade a config document from the version of their index + 1
+// to the next one.
+var migrations = []func(doc map[string]any) error{
+	migrateV1,
+}
+
+// migrateV1 renames the non-interactive key of version 1 to nonInteractive
+// and moves its workspaceTemplate key into the workspace section.
+func migrateV1(doc map[string]any) error {
+	if key, value, ok := popKey(doc, "non-interactive"); ok {
+		if _, _, found := popKey(doc, "nonInteractive"); found {
+			return FieldError{Key: key, Message: "set together with nonInteractive; keep only nonInteractive"}
+		}
+		doc["nonInteractive"] = value
+	}
+	key, value, ok := popKey(doc, "workspaceTemplate")
+	if !ok {
+		return nil
+	}
+	workspace := map[string]any{}
+	if _, existing, found := popKey(doc, "workspace"); found {
+		section, isMap := existing.(map[string]any)
+		if !isMap {
+			return FieldError{Key: "workspace", Message: "expected a section"}
+		}
+		workspace = section
+	}
+	if _, _, found := popKey(workspace, "template"); found {
+		return FieldError{Key: key, Message: "set together with workspace.template; keep only workspace.template"}
+	}
+	workspace["template"] = value
+	doc["workspace"] = workspace
+	return nil
+}
+
+// popKey removes the key of doc equal to name ignoring case, as viper reads
+// keys, and returns it with its value.
+func popKey(doc map[string]any, name string) (string, any, bool) {
+	for k, v := range doc {
+		if strings.EqualFold(k, name) {
+			delete(doc, k)
+			return k, v, true
+		}
+	}
+	return "", nil, false
+}
+
+// Migrate upgrades doc, a decoded config file, to CurrentVersion in place
+// and returns the version it had. Files of a newer CLI are rejected.
+func Migrate(doc map[string]any) (int, error) {
+	version := 1
+	if _, v, ok := popKey(doc, "version"); ok {
+		n, isInt := v.(int)
+		if !isInt || n < 1 {
+			return 0, FieldError{Key: "version", Message: fmt.Sprintf("must be a schema version from 1 to %d, got %v", CurrentVersion, v)}
+		}
+		version = n
+	}
+	if version > CurrentVersion {
+		return version, FieldError{Key: "version", Message: fmt.Sprintf("schema version %d is newer than this CLI supports (%d); upgrade localplane", version, CurrentVersion)}
+	}
+	for v := version; v < CurrentVersion; v++ {
+		if err := migrations[v-1](doc); err != nil {
+			return version, fmt.Errorf("migrating from version %d: %w", v, err)
+		}
+	}
+	doc["version"] = CurrentVersion
+	return version, nil
+}
+
+// ReadFile decodes the YAML config file at path and migrates it to
+// CurrentVersion, returning the version it had. A missing file is an empty
+// document of the current version.
+func ReadFile(path string) (map[string]any, int, error) {
+	doc := map[string]any{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		doc["version"] = CurrentVersion
+		return doc, CurrentVersion, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, 0, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if doc == nil {
+		doc = map[string]any{}
+	}
+	version, err := Migrate(doc)
+	if err != nil {
+		return nil, version, fmt.Errorf("%s: %w", path, err)
+	}
+	return doc, version, nil
+}
+
+// WriteFile encodes doc as YAML into path, creating its directory.
+func WriteFile(path string, doc map[string]any) error {
+	data, err := MarshalYAML(doc)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// EditFile applies edit to the config file at path, migrated to the
+// current schema version and decoded as c, then writes it back. The file is
+// only written when its values stay valid.
+func EditFile(path string, edit func(doc map[string]any, c Config) error) error {
+	doc, _, err := ReadFile(path)
+	if err != nil {
+		return err
+	}
+	c, err := Decode(doc)
+	if err != nil {
+		return fmt.Errorf("%s:\n%w", path, err)
+	}
+	if err := edit(doc, c); err != nil {
+		return err
+	}
+	c, err = Decode(doc)
+	if err = errors.Join(err, c.Validate()); err != nil {
+		return fmt.Errorf("not saving an invalid config file %s:\n%w", path, err)
+	}
+	return WriteFile(path, doc)
+}
+
+// MarshalYAML encodes v as YAML indented like the config files.
+func MarshalYAML(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
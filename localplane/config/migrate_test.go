@@ -0,0 +1,154 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// parseDoc decodes a YAML config document.
+func parseDoc(t *testing.T, data string) map[string]any {
+	t.Helper()
+	doc := map[string]any{}
+	if err := yaml.Unmarshal([]byte(data), &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		want    string
+		from    int
+		errKey  string
+		errText string
+	}{
+		{
+			name: "empty v1 file",
+			doc:  "{}",
+			want: "version: 2",
+			from: 1,
+		},
+		{
+			name: "v1 file with workspaceTemplate",
+			doc:  "workspaceTemplate: github:acme/platform//tpl\ncluster:\n  name: dev\n",
+			want: "version: 2\nworkspace:\n  template: github:acme/platform//tpl\ncluster:\n  name: dev\n",
+			from: 1,
+		},
+		{
+			name: "v1 keys matched ignoring case",
+			doc:  "workspacetemplate: builtin\nNon-Interactive: true\n",
+			want: "version: 2\nworkspace:\n  template: builtin\nnonInteractive: true\n",
+			from: 1,
+		},
+		{
+			name: "v1 workspaceTemplate next to other workspace keys",
+			doc:  "workspaceTemplate: builtin\nworkspace:\n  templateRef: v1.2.0\n",
+			want: "version: 2\nworkspace:\n  template: builtin\n  templateRef: v1.2.0\n",
+			from: 1,
+		},
+		{
+			name:    "v1 file with both workspaceTemplate and workspace.template",
+			doc:     "workspaceTemplate: builtin\nworkspace:\n  template: github:acme/platform\n",
+			errKey:  "workspaceTemplate",
+			errText: "keep only workspace.template",
+		},
+		{
+			name:    "v1 workspace key that isn't a section",
+			doc:     "workspaceTemplate: builtin\nworkspace: shop\n",
+			errKey:  "workspace",
+			errText: "expected a section",
+		},
+		{
+			name: "v1 file with non-interactive",
+			doc:  "version: 1\nnon-interactive: true\n",
+			want: "version: 2\nnonInteractive: true\n",
+			from: 1,
+		},
+		{
+			name:    "v1 file with both non-interactive and nonInteractive",
+			doc:     "non-interactive: true\nnonInteractive: false\n",
+			errKey:  "non-interactive",
+			errText: "keep only nonInteractive",
+		},
+		{
+			name: "current version left as is",
+			doc:  "version: 2\nworkspace:\n  template: builtin\nnonInteractive: true\n",
+			want: "version: 2\nworkspace:\n  template: builtin\nnonInteractive: true\n",
+			from: 2,
+		},
+		{
+			name: "v1 keys in a v2 file aren't migrated",
+			doc:  "version: 2\nworkspaceTemplate: builtin\n",
+			want: "version: 2\nworkspaceTemplate: builtin\n",
+			from: 2,
+		},
+		{
+			name:    "newer version",
+			doc:     "version: 99\n",
+			errKey:  "version",
+			errText: "newer than this CLI supports",
+		},
+		{
+			name:    "version as a string",
+			doc:     "version: \"2\"\n",
+			errKey:  "version",
+			errText: "must be a schema version from 1 to 2",
+		},
+		{
+			name:    "version zero",
+			doc:     "version: 0\n",
+			errKey:  "version",
+			errText: "must be a schema version",
+		},
+		{
+			name:    "version as a float",
+			doc:     "version: 1.5\n",
+			errKey:  "version",
+			errText: "must be a schema version",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := parseDoc(t, tt.doc)
+			from, err := Migrate(doc)
+			if tt.errKey != "" {
+				fieldErrs := FieldErrors(err)
+				if len(fieldErrs) != 1 || fieldErrs[0].Key != tt.errKey || !strings.Contains(err.Error(), tt.errText) {
+					t.Fatalf("Migrate() error = %v; want a %s error containing %q", err, tt.errKey, tt.errText)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Migrate() error = %v", err)
+			}
+			if from != tt.from {
+				t.Errorf("Migrate() version = %d; want %d", from, tt.from)
+			}
+			if want := parseDoc(t, tt.want); !reflect.DeepEqual(doc, want) {
+				t.Errorf("Migrate() doc = %v; want %v", doc, want)
+			}
+		})
+	}
+}
+
+func TestMigratedFileDecodes(t *testing.T) {
+	doc := parseDoc(t, "workspaceTemplate: github:acme/platform\nnon-interactive: true\ncluster:\n  domain: dev.test\n")
+	if _, err := Migrate(doc); err != nil {
+		t.Fatal(err)
+	}
+	if err := UnknownKeys(doc); err != nil {
+		t.Errorf("UnknownKeys() = %v; want none after migration", err)
+	}
+	c, err := Decode(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Workspace.Template != "github:acme/platform" || !c.NonInteractive || c.Cluster.Domain != "dev.test" {
+		t.Errorf("Decode() = %+v; want the migrated values", c)
+	}
+}
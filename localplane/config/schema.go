@@ -0,0 +1,248 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Key is a settable key of the config file.
+type Key struct {
+	// Name is the dotted path of the key, e.g. `cluster.domain`.
+	Name string
+	Type reflect.Type
+}
+
+// Keys returns the keys of the schema: the scalar and list fields of Config
+// and of its sections, sorted by name.
+func Keys() []Key {
+	var keys []Key
+	walkKeys(reflect.TypeOf(Config{}), "", func(name string, t reflect.Type) {
+		keys = append(keys, Key{Name: name, Type: t})
+	})
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Name < keys[j].Name })
+	return keys
+}
+
+// LookupKey returns the key named name, ignoring case. Unknown keys get a
+// FieldError suggesting the closest one.
+func LookupKey(name string) (Key, error) {
+	keys := Keys()
+	names := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if strings.EqualFold(key.Name, name) {
+			return key, nil
+		}
+		names = append(names, key.Name)
+	}
+	return Key{}, unknownKey(name, names)
+}
+
+// walkKeys calls fn with the dotted name and type of the leaf fields of t.
+func walkKeys(t reflect.Type, prefix string, fn func(name string, t reflect.Type)) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := prefix + tagName(field)
+		if field.Type.Kind() == reflect.Struct {
+			walkKeys(field.Type, name+".", fn)
+			continue
+		}
+		fn(name, field.Type)
+	}
+}
+
+// tagName returns the config key of a struct field.
+func tagName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// fieldByKey returns the field of t whose key is name, ignoring case.
+func fieldByKey(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if strings.EqualFold(tagName(t.Field(i)), name) {
+			return t.Field(i), true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// keyNames returns the keys of the fields of t.
+func keyNames(t reflect.Type) []string {
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		names = append(names, tagName(t.Field(i)))
+	}
+	return names
+}
+
+// ToMap returns c as nested maps keyed like the config file, durations
+// formatted as strings, ready to be encoded as YAML or JSON.
+func ToMap(c Config) map[string]any {
+	return toValue(reflect.ValueOf(c)).(map[string]any)
+}
+
+// toValue converts v for ToMap.
+func toValue(v reflect.Value) any {
+	switch {
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).String()
+	case v.Kind() == reflect.Struct:
+		m := make(map[string]any, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			m[tagName(v.Type().Field(i))] = toValue(v.Field(i))
+		}
+		return m
+	case v.Kind() == reflect.Slice:
+		list := make([]any, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			list = append(list, toValue(v.Index(i)))
+		}
+		return list
+	default:
+		return v.Interface()
+	}
+}
+
+// Defaults returns the values of Default by dotted key, for viper.SetDefault.
+// Lists of sections (repositories) have no default.
+func Defaults() map[string]any {
+	defaults := map[string]any{}
+	def := reflect.ValueOf(Default())
+	for _, key := range Keys() {
+		if key.Type.Kind() == reflect.Slice && key.Type.Elem().Kind() == reflect.Struct {
+			continue
+		}
+		v := def
+		for _, part := range strings.Split(key.Name, ".") {
+			field, _ := fieldByKey(v.Type(), part)
+			v = v.FieldByIndex(field.Index)
+		}
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			defaults[key.Name] = reflect.MakeSlice(v.Type(), 0, 0).Interface()
+			continue
+		}
+		defaults[key.Name] = v.Interface()
+	}
+	return defaults
+}
+
+// Get returns the value of the dotted key name in m, as returned by ToMap,
+// ignoring case.
+func Get(m map[string]any, name string) (any, bool) {
+	var value any = m
+	for _, part := range strings.Split(name, ".") {
+		section, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		found := false
+		for k, v := range section {
+			if strings.EqualFold(k, part) {
+				value, found = v, true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// unknownKey returns the FieldError of the unknown key name, suggesting the
+// closest of known.
+func unknownKey(name string, known []string) FieldError {
+	msg := "unknown key"
+	if suggestion := closest(name, known); suggestion != "" {
+		msg = fmt.Sprintf("unknown key (did you mean %s?)", suggestion)
+	}
+	return FieldError{Key: name, Message: msg}
+}
+
+// closest returns the candidate within an edit distance of a third of name,
+// or "".
+func closest(name string, candidates []string) string {
+	best, bestDist := "", len(name)/3+1
+	for _, c := range candidates {
+		if d := editDistance(strings.ToLower(name), strings.ToLower(c)); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance of a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// Set sets the dotted key name of doc, a config document, to value,
+// creating its sections. Existing keys are matched ignoring case and
+// renamed to name.
+func Set(doc map[string]any, name string, value any) error {
+	parts := strings.Split(name, ".")
+	section := doc
+	for i, part := range parts[:len(parts)-1] {
+		_, existing, ok := popKey(section, part)
+		next, isMap := existing.(map[string]any)
+		if ok && !isMap {
+			return FieldError{Key: strings.Join(parts[:i+1], "."), Message: "expected a section"}
+		}
+		if !ok {
+			next = map[string]any{}
+		}
+		section[part] = next
+		section = next
+	}
+	last := parts[len(parts)-1]
+	popKey(section, last)
+	section[last] = value
+	return nil
+}
+
+// Unset removes the dotted key name from doc, and the sections it leaves
+// empty. It reports whether the key was set.
+func Unset(doc map[string]any, name string) bool {
+	part, rest, nested := strings.Cut(name, ".")
+	if !nested {
+		_, _, ok := popKey(doc, part)
+		return ok
+	}
+	key, value, ok := popKey(doc, part)
+	section, isMap := value.(map[string]any)
+	if !ok || !isMap {
+		if ok {
+			doc[key] = value
+		}
+		return false
+	}
+	removed := Unset(section, rest)
+	if len(section) > 0 {
+		doc[key] = section
+	}
+	return removed
+}
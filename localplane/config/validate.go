@@ -0,0 +1,244 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"localplane/utils/progress"
+
+	"github.com/spf13/viper"
+)
+
+var (
+	dnsLabelRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	domainRegexp   = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+)
+
+// FieldError is an invalid value of a config key.
+type FieldError struct {
+	// Key is the dotted path of the key, e.g. `cluster.domain`.
+	Key     string `json:"key"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	if e.Key == "" {
+		return e.Message
+	}
+	return e.Key + ": " + e.Message
+}
+
+// FieldErrors flattens the errors joined in err into FieldErrors; the other
+// errors have no Key.
+func FieldErrors(err error) []FieldError {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var fieldErrs []FieldError
+		for _, e := range joined.Unwrap() {
+			fieldErrs = append(fieldErrs, FieldErrors(e)...)
+		}
+		return fieldErrs
+	}
+	var fieldErr FieldError
+	if errors.As(err, &fieldErr) {
+		return []FieldError{fieldErr}
+	}
+	return []FieldError{{Message: err.Error()}}
+}
+
+// UnknownKeys returns a FieldError for each key of doc, a migrated config
+// file, missing from the schema, joined.
+func UnknownKeys(doc map[string]any) error {
+	errs := unknownKeys(reflect.TypeOf(Config{}), doc, "")
+	slices.SortFunc(errs, func(a, b error) int { return strings.Compare(a.Error(), b.Error()) })
+	return errors.Join(errs...)
+}
+
+// Decode decodes doc, a migrated config file, on top of Default. Unknown
+// keys are ignored (see UnknownKeys).
+func Decode(doc map[string]any) (Config, error) {
+	v := viper.New()
+	for key, value := range Defaults() {
+		v.SetDefault(key, value)
+	}
+	// viper lowercases the keys of the maps it merges
+	if err := v.MergeConfigMap(copyDoc(doc)); err != nil {
+		return Config{}, err
+	}
+	return unmarshal(v)
+}
+
+// Load returns the configuration read by v, checked with Validate. The
+// errors are joined; the returned Config holds the keys decoded anyway.
+func Load(v *viper.Viper) (Config, error) {
+	c, err := unmarshal(v)
+	if err != nil {
+		return c, err
+	}
+	return c, c.Validate()
+}
+
+// unmarshal decodes the keys of v, turning the decoding errors into
+// FieldErrors.
+func unmarshal(v *viper.Viper) (Config, error) {
+	var c Config
+	err := v.Unmarshal(&c)
+	if err == nil {
+		return c, nil
+	}
+	// mapstructure lists the errors one per line, as `'<key>' <message>`
+	var errs []error
+	_, list, found := strings.Cut(err.Error(), "error(s):")
+	if !found {
+		return c, err
+	}
+	for _, line := range strings.Split(list, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fieldErr := FieldError{Message: line}
+		if strings.HasPrefix(line, "'") {
+			if key, msg, ok := strings.Cut(line[1:], "' "); ok {
+				fieldErr = FieldError{Key: key, Message: msg}
+			}
+		}
+		errs = append(errs, fieldErr)
+	}
+	return c, errors.Join(errs...)
+}
+
+// copyDoc returns a deep copy of the sections of doc.
+func copyDoc(doc map[string]any) map[string]any {
+	c := make(map[string]any, len(doc))
+	for k, v := range doc {
+		if section, ok := v.(map[string]any); ok {
+			v = copyDoc(section)
+		}
+		c[k] = v
+	}
+	return c
+}
+
+// unknownKeys returns a FieldError for each key of doc without a field in t.
+func unknownKeys(t reflect.Type, doc map[string]any, prefix string) []error {
+	var errs []error
+	known := keyNames(t)
+	for i := range known {
+		known[i] = prefix + known[i]
+	}
+	for k, value := range doc {
+		field, ok := fieldByKey(t, k)
+		if !ok {
+			errs = append(errs, unknownKey(prefix+k, known))
+			continue
+		}
+		name := prefix + tagName(field)
+		switch {
+		case field.Type.Kind() == reflect.Struct:
+			section, isMap := value.(map[string]any)
+			if !isMap {
+				errs = append(errs, FieldError{Key: name, Message: "expected a section"})
+				continue
+			}
+			errs = append(errs, unknownKeys(field.Type, section, name+".")...)
+		case field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.Struct:
+			list, isList := value.([]any)
+			if !isList {
+				errs = append(errs, FieldError{Key: name, Message: "expected a list"})
+				continue
+			}
+			for i, item := range list {
+				entry, isMap := item.(map[string]any)
+				if !isMap {
+					errs = append(errs, FieldError{Key: fmt.Sprintf("%s[%d]", name, i), Message: "expected a section"})
+					continue
+				}
+				errs = append(errs, unknownKeys(field.Type.Elem(), entry, fmt.Sprintf("%s[%d].", name, i))...)
+			}
+		}
+	}
+	return errs
+}
+
+// Validate checks the values of c, returning the FieldErrors joined. Empty
+// strings stand for the default of their key.
+func (c Config) Validate() error {
+	var errs []error
+	invalid := func(key, format string, args ...any) {
+		errs = append(errs, FieldError{Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+	if c.Version > CurrentVersion {
+		invalid("version", "schema version %d is newer than this CLI supports (%d)", c.Version, CurrentVersion)
+	}
+	if c.Progress != "" && !slices.Contains(progress.Modes(), c.Progress) {
+		invalid("progress", "must be one of %s, got %q", strings.Join(progress.Modes(), ", "), c.Progress)
+	}
+	names := map[string]bool{}
+	paths := map[string]string{}
+	for i, ws := range c.Workspaces {
+		key := fmt.Sprintf("workspaces[%d]", i)
+		switch {
+		case len(ws.Name) > 63 || !dnsLabelRegexp.MatchString(ws.Name):
+			invalid(key+".name", "must be a lowercase DNS label (letters, digits and '-'), got %q", ws.Name)
+		case names[ws.Name]:
+			invalid(key+".name", "workspace %s is defined twice", ws.Name)
+		}
+		names[ws.Name] = true
+		path := filepath.Clean(ws.Path)
+		switch {
+		case !filepath.IsAbs(ws.Path):
+			invalid(key+".path", "must be an absolute path, got %q", ws.Path)
+		case paths[path] != "":
+			invalid(key+".path", "%s is already the directory of workspace %s", ws.Path, paths[path])
+		default:
+			paths[path] = ws.Name
+		}
+	}
+	if c.CurrentWorkspace != "" && !names[c.CurrentWorkspace] {
+		invalid("currentWorkspace", "no workspace named %q (see `localplane workspace list`)", c.CurrentWorkspace)
+	}
+	if c.Cluster.Name != "" && (len(c.Cluster.Name) > 63 || !dnsLabelRegexp.MatchString(c.Cluster.Name)) {
+		invalid("cluster.name", "must be a lowercase DNS label (letters, digits and '-'), got %q", c.Cluster.Name)
+	}
+	if c.Cluster.Domain != "" && (len(c.Cluster.Domain) > 253 || !domainRegexp.MatchString(c.Cluster.Domain)) {
+		invalid("cluster.domain", "must be a lowercase DNS name such as localplane or dev.example.test, got %q", c.Cluster.Domain)
+	}
+	if c.Cluster.LoadBalancer != "" && !slices.Contains(LoadBalancerProviders(), c.Cluster.LoadBalancer) {
+		invalid("cluster.loadBalancer", "must be one of %s, got %q", strings.Join(LoadBalancerProviders(), ", "), c.Cluster.LoadBalancer)
+	}
+	if c.Cluster.DNS != "" && !slices.Contains(DNSBackends(), c.Cluster.DNS) {
+		invalid("cluster.dns", "must be one of %s, got %q", strings.Join(DNSBackends(), ", "), c.Cluster.DNS)
+	}
+	if c.ArgoCD.ChartVersion != "" && c.ArgoCD.ChartPath != "" {
+		invalid("argocd.chartPath", "set together with argocd.chartVersion; a local chart has no version")
+	}
+	for key, d := range map[string]time.Duration{
+		"timeouts.clusterReady":        c.Timeouts.ClusterReady,
+		"timeouts.loadBalancerService": c.Timeouts.LoadBalancerService,
+		"timeouts.helmInstall":         c.Timeouts.HelmInstall,
+		"timeouts.clusterDeleted":      c.Timeouts.ClusterDeleted,
+		"retry.initialInterval":        c.Retry.InitialInterval,
+		"retry.maxInterval":            c.Retry.MaxInterval,
+	} {
+		if d < 0 {
+			invalid(key, "must not be negative, got %v", d)
+		}
+	}
+	if c.Retry.Attempts < 0 {
+		invalid("retry.attempts", "must not be negative, got %d", c.Retry.Attempts)
+	}
+	if c.Retry.Multiplier != 0 && c.Retry.Multiplier < 1 {
+		invalid("retry.multiplier", "must be at least 1, got %v", c.Retry.Multiplier)
+	}
+	slices.SortFunc(errs, func(a, b error) int { return strings.Compare(a.Error(), b.Error()) })
+	return errors.Join(errs...)
+}
@@ -0,0 +1,241 @@
+package config
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateWorkspaces(t *testing.T) {
+	tests := []struct {
+		name       string
+		workspaces []WorkspaceDirectoryConfig
+		current    string
+		want       []string
+	}{
+		{
+			name: "distinct",
+			workspaces: []WorkspaceDirectoryConfig{
+				{Name: "shop", Path: "/srv/shop"},
+				{Name: "billing", Path: "/srv/billing"},
+			},
+			current: "shop",
+		},
+		{
+			name: "nested paths",
+			workspaces: []WorkspaceDirectoryConfig{
+				{Name: "shop", Path: "/srv/shop"},
+				{Name: "billing", Path: "/srv/shop/billing"},
+			},
+		},
+		{
+			name: "duplicate name",
+			workspaces: []WorkspaceDirectoryConfig{
+				{Name: "shop", Path: "/srv/shop"},
+				{Name: "shop", Path: "/srv/other"},
+			},
+			want: []string{"workspaces[1].name"},
+		},
+		{
+			name: "duplicate path",
+			workspaces: []WorkspaceDirectoryConfig{
+				{Name: "shop", Path: "/srv/shop"},
+				{Name: "billing", Path: "/srv/shop"},
+			},
+			want: []string{"workspaces[1].path"},
+		},
+		{
+			name: "duplicate path once cleaned",
+			workspaces: []WorkspaceDirectoryConfig{
+				{Name: "shop", Path: "/srv/shop"},
+				{Name: "billing", Path: "/srv/./billing/../shop/"},
+			},
+			want: []string{"workspaces[1].path"},
+		},
+		{
+			name:       "bad name and relative path",
+			workspaces: []WorkspaceDirectoryConfig{{Name: "Shop", Path: "shop"}},
+			want:       []string{"workspaces[0].name", "workspaces[0].path"},
+		},
+		{
+			name:       "unknown current workspace",
+			workspaces: []WorkspaceDirectoryConfig{{Name: "shop", Path: "/srv/shop"}},
+			current:    "billing",
+			want:       []string{"currentWorkspace"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Config{Workspaces: tt.workspaces, CurrentWorkspace: tt.current}.Validate()
+			if got := errorKeys(err); !slices.Equal(got, tt.want) {
+				t.Errorf("Validate() = %v; want errors for %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// errorKeys returns the sorted keys of the FieldErrors of err.
+func errorKeys(err error) []string {
+	var keys []string
+	for _, e := range FieldErrors(err) {
+		keys = append(keys, e.Key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func TestUnknownKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want []string
+	}{
+		{
+			name: "known keys",
+			doc:  "version: 2\ncluster:\n  loadBalancer: cloud-provider-kind\nworkspaces:\n  - name: shop\n    path: /srv/shop\n",
+		},
+		{
+			name: "keys matched ignoring case",
+			doc:  "Cluster:\n  LOADBALANCER: none\n",
+		},
+		{
+			name: "typo with a suggestion",
+			doc:  "cluster:\n  lodBalancer: none\n",
+			want: []string{"cluster.lodBalancer: unknown key (did you mean cluster.loadBalancer?)"},
+		},
+		{
+			name: "top-level typo with a suggestion",
+			doc:  "directroy: /srv\n",
+			want: []string{"directroy: unknown key (did you mean directory?)"},
+		},
+		{
+			name: "unknown key without a suggestion",
+			doc:  "colour: blue\n",
+			want: []string{"colour: unknown key"},
+		},
+		{
+			name: "unknown key of a list entry",
+			doc:  "repositories:\n  - name: platform\n    branh: main\n",
+			want: []string{"repositories[0].branh: unknown key (did you mean repositories[0].branch?)"},
+		},
+		{
+			name: "scalar where a section is expected",
+			doc:  "timeouts: 5m\n",
+			want: []string{"timeouts: expected a section"},
+		},
+		{
+			name: "v1 key left in a v2 file",
+			doc:  "version: 2\nworkspaceTemplate: builtin\n",
+			want: []string{"workspaceTemplate: unknown key"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, e := range FieldErrors(UnknownKeys(parseDoc(t, tt.doc))) {
+				got = append(got, e.Error())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("UnknownKeys() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		check   func(c Config) bool
+		errKeys []string
+	}{
+		{
+			name: "defaults",
+			doc:  "version: 2\n",
+			check: func(c Config) bool {
+				return c.Timeouts == Default().Timeouts && c.Cluster.Domain == Default().Cluster.Domain
+			},
+		},
+		{
+			name: "durations",
+			doc:  "timeouts:\n  clusterReady: 10m\nretry:\n  maxInterval: 1m30s\n",
+			check: func(c Config) bool {
+				return c.Timeouts.ClusterReady == 10*time.Minute && c.Retry.MaxInterval == 90*time.Second
+			},
+		},
+		{
+			name:    "bad duration",
+			doc:     "timeouts:\n  clusterReady: soon\n",
+			errKeys: []string{"timeouts.clusterReady"},
+		},
+		{
+			name:    "duration without a unit",
+			doc:     "retry:\n  initialInterval: \"5\"\n",
+			errKeys: []string{"retry.initialInterval"},
+		},
+		{
+			name:    "bad int",
+			doc:     "retry:\n  attempts: many\n",
+			errKeys: []string{"retry.attempts"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Decode(parseDoc(t, tt.doc))
+			if got := errorKeys(err); !slices.Equal(got, tt.errKeys) {
+				t.Fatalf("Decode() error = %v; want errors for %v", err, tt.errKeys)
+			}
+			if tt.check != nil && !tt.check(c) {
+				t.Errorf("Decode() = %+v", c)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(c *Config)
+		want []string
+	}{
+		{name: "defaults", edit: func(c *Config) {}},
+		{name: "empty strings stand for the defaults", edit: func(c *Config) { c.Cluster = ClusterConfig{} }},
+		{name: "subdomain", edit: func(c *Config) { c.Cluster.Domain = "dev.example.test" }},
+		{name: "uppercase domain", edit: func(c *Config) { c.Cluster.Domain = "Dev.test" }, want: []string{"cluster.domain"}},
+		{name: "domain with an empty label", edit: func(c *Config) { c.Cluster.Domain = "dev..test" }, want: []string{"cluster.domain"}},
+		{name: "domain with a trailing dot", edit: func(c *Config) { c.Cluster.Domain = "dev.test." }, want: []string{"cluster.domain"}},
+		{name: "cluster name with an underscore", edit: func(c *Config) { c.Cluster.Name = "my_cluster" }, want: []string{"cluster.name"}},
+		{name: "cluster name starting with a dash", edit: func(c *Config) { c.Cluster.Name = "-dev" }, want: []string{"cluster.name"}},
+		{name: "cluster name too long", edit: func(c *Config) { c.Cluster.Name = strings.Repeat("a", 64) }, want: []string{"cluster.name"}},
+		{name: "unknown load balancer", edit: func(c *Config) { c.Cluster.LoadBalancer = "metallb" }, want: []string{"cluster.loadBalancer"}},
+		{name: "unknown dns", edit: func(c *Config) { c.Cluster.DNS = "bind" }, want: []string{"cluster.dns"}},
+		{name: "unknown progress", edit: func(c *Config) { c.Progress = "fancy" }, want: []string{"progress"}},
+		{
+			name: "chart path with a version",
+			edit: func(c *Config) { c.ArgoCD.ChartPath, c.ArgoCD.ChartVersion = "./argo-cd", "7.0.0" },
+			want: []string{"argocd.chartPath"},
+		},
+		{
+			name: "negative durations",
+			edit: func(c *Config) { c.Timeouts.ClusterReady, c.Retry.MaxInterval = -time.Second, -time.Minute },
+			want: []string{"retry.maxInterval", "timeouts.clusterReady"},
+		},
+		{
+			name: "bad retry",
+			edit: func(c *Config) { c.Retry.Attempts, c.Retry.Multiplier = -1, 0.5 },
+			want: []string{"retry.attempts", "retry.multiplier"},
+		},
+		{name: "newer version", edit: func(c *Config) { c.Version = CurrentVersion + 1 }, want: []string{"version"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Default()
+			tt.edit(&c)
+			err := c.Validate()
+			if got := errorKeys(err); !slices.Equal(got, tt.want) {
+				t.Errorf("Validate() = %v; want errors for %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package config
+
+// LookupWorkspace returns the workspace of c named name.
+func (c Config) LookupWorkspace(name string) (WorkspaceDirectoryConfig, bool) {
+	for _, ws := range c.Workspaces {
+		if ws.Name == name {
+			return ws, true
+		}
+	}
+	return WorkspaceDirectoryConfig{}, false
+}
+
+// SetWorkspaces replaces the workspaces of doc, a config document, with
+// workspaces; the key is removed when there are none.
+func SetWorkspaces(doc map[string]any, workspaces []WorkspaceDirectoryConfig) error {
+	if len(workspaces) == 0 {
+		Unset(doc, "workspaces")
+		return nil
+	}
+	return Set(doc, "workspaces", ToMap(Config{Workspaces: workspaces})["workspaces"])
+}
@@ -0,0 +1,23 @@
+package localplane
+
+import (
+	"context"
+	"slices"
+
+	"localplane/utils/clierror"
+	kindsvc "localplane/utils/kind"
+)
+
+// checkClusterAbsent fails with clierror.ExitUsage when a kind cluster named
+// clusterName already exists, so that a failed creation never rolls back a
+// cluster it didn't start.
+func checkClusterAbsent(ctx context.Context, kindClient *kindsvc.Client, clusterName string) error {
+	names, err := kindClient.List(ctx)
+	if err != nil {
+		return clierror.New(clierror.ExitCluster, err)
+	}
+	if slices.Contains(names, clusterName) {
+		return clierror.Newf(clierror.ExitUsage, "a kind cluster named %s already exists; destroy it first or pick another --cluster-name", clusterName)
+	}
+	return nil
+}
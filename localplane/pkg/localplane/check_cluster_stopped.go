@@ -0,0 +1,35 @@
+package localplane
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	kindsvc "localplane/utils/kind"
+	"localplane/utils/retry"
+
+	"github.com/rs/zerolog/log"
+)
+
+// waitForClusterStopped polls the kind clusters, with the backoff of policy,
+// until the cluster is no longer listed. It fails when the cluster is still
+// listed after timeout or ctx is done.
+func waitForClusterStopped(ctx context.Context, kindClient *kindsvc.Client, clusterName string, timeout time.Duration, policy retry.Policy) error {
+	err := retry.Until(ctx, policy, timeout, func(ctx context.Context) (bool, error) {
+		names, err := kindClient.List(ctx)
+		if err != nil {
+			log.Debug().Err(err).Msg("failed to list kind clusters")
+			return false, err
+		}
+		if slices.Contains(names, clusterName) {
+			return false, fmt.Errorf("cluster %s still listed", clusterName)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("waiting for cluster %s to be removed: %w", clusterName, err)
+	}
+	log.Info().Str("name", clusterName).Msg("cluster confirmed removed")
+	return nil
+}
@@ -0,0 +1,34 @@
+package localplane
+
+import (
+	"fmt"
+
+	gitutil "localplane/utils/git"
+
+	"github.com/rs/zerolog/log"
+)
+
+// checkoutWorkspaceBranch checks out branch in the cluster's local-argo repo,
+// creating it from the current branch when missing, so the workspace values
+// written by the CLI are committed to the branch the GitOps engine tracks.
+// It fails when the branch can't be checked out (e.g. the working tree has
+// conflicting uncommitted changes).
+func checkoutWorkspaceBranch(repoPath, branch string) error {
+	if repoPath == "" {
+		return nil
+	}
+	gitClient := gitutil.NewClient(repoPath)
+	current, err := gitClient.CurrentBranch()
+	if err != nil {
+		return fmt.Errorf("reading the current branch of the local-argo repo: %w", err)
+	}
+	if current == branch {
+		log.Debug().Str("branch", branch).Msg("local-argo repo already on the tracked branch")
+		return nil
+	}
+	if err := gitClient.Checkout(branch); err != nil {
+		return fmt.Errorf("checking out the tracked branch of the local-argo repo: %w", err)
+	}
+	log.Info().Str("path", repoPath).Str("from", current).Str("branch", branch).Msg("checked out tracked branch of the local-argo repo")
+	return nil
+}
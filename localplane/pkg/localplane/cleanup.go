@@ -0,0 +1,35 @@
+package localplane
+
+import (
+	"fmt"
+	"os"
+
+	"localplane/utils/kubeconfig"
+
+	"github.com/rs/zerolog/log"
+)
+
+// removeKubeconfig removes the kubeconfig of the cluster.
+func removeKubeconfig(dir, clusterName string) error {
+	kubeconfigPath := KubeconfigPath(dir, clusterName)
+
+	if err := os.Remove(kubeconfigPath); err != nil {
+		return fmt.Errorf("deleting kubeconfig file: %w", err)
+	}
+	log.Info().Str("path", kubeconfigPath).Msg("deleted kubeconfig file")
+	return nil
+}
+
+// unmergeKubeconfig removes the context of the cluster from the kubeconfig
+// of the user, and reports whether it was there.
+func unmergeKubeconfig(userKubeconfig, clusterName string) (bool, error) {
+	client := kubeconfig.NewClient(userKubeconfig)
+	removed, err := client.Unmerge(clusterName)
+	if err != nil {
+		return false, fmt.Errorf("removing context %s from the kubeconfig: %w", kubeconfig.ContextName(clusterName), err)
+	}
+	if removed {
+		log.Info().Str("context", kubeconfig.ContextName(clusterName)).Str("kubeconfig", client.Filename()).Msg("removed the cluster context from the kubeconfig")
+	}
+	return removed, nil
+}
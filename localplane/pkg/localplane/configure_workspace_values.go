@@ -0,0 +1,44 @@
+package localplane
+
+import (
+	"path/filepath"
+
+	gitutil "localplane/utils/git"
+	"localplane/utils/helmvalues"
+	"localplane/utils/ingress"
+
+	"github.com/rs/zerolog/log"
+)
+
+// configureWorkspaceValues sets `ingress.type`, `gitops.engine` and `domain`
+// of the localplane-addons values in the cluster's local-argo workspace chart
+// and commits the change so the GitOps engine deploys the matching addons.
+func configureWorkspaceValues(repoPath string, controller ingress.Controller, gitopsEngine, domain string) {
+	if repoPath == "" {
+		log.Debug().Msg("skipping workspace values configuration; no local-argo repo available")
+		return
+	}
+
+	valuesPath := filepath.Join(repoPath, "charts", "workspace", "values", "localplane-addons.values.yaml")
+	settings := []struct {
+		key   string
+		value string
+	}{
+		{"localplane-addons.ingress.type", controller.Type},
+		{"localplane-addons.gitops.engine", gitopsEngine},
+		{"localplane-addons.domain", domain},
+	}
+	for _, s := range settings {
+		if err := helmvalues.Set(valuesPath, s.key, s.value); err != nil {
+			log.Error().Err(err).Str("path", valuesPath).Str("key", s.key).Msg("failed to update workspace values")
+			return
+		}
+	}
+
+	gitClient := gitutil.NewClient(repoPath)
+	if err := gitClient.CommitAll("Use " + controller.Type + " ingress controller and " + gitopsEngine + " gitops engine"); err != nil {
+		log.Debug().Err(err).Str("path", repoPath).Msg("nothing committed while configuring workspace values (unchanged?)")
+	} else {
+		log.Info().Str("ingress", controller.Type).Str("gitops", gitopsEngine).Msg("updated workspace values")
+	}
+}
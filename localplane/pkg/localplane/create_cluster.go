@@ -0,0 +1,280 @@
+package localplane
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"localplane/utils/ca"
+	"localplane/utils/clierror"
+	"localplane/utils/gitops"
+	"localplane/utils/ingress"
+	kindsvc "localplane/utils/kind"
+	kindcfg "localplane/utils/kind/config"
+	"localplane/utils/kubeconfig"
+	"localplane/utils/kubectl"
+	"localplane/utils/progress"
+
+	"github.com/rs/zerolog/log"
+)
+
+// CreateCluster creates a kind cluster with its local-argo repo, GitOps
+// engine and addons, as `localplane cluster create` does. The result is
+// returned even on failure, with the steps run so far. Errors are classified
+// with clierror (see clierror.Code); once ctx is done they are
+// clierror.ExitAborted. With CleanupOnFailure, a failed or interrupted
+// creation deletes the kind cluster it started.
+func CreateCluster(ctx context.Context, opts Options) (*CreateResult, error) {
+	result := &CreateResult{}
+	st := progress.NewSteps(opts.Reporter)
+	err := createCluster(ctx, opts, result, st)
+	result.Steps = st.List()
+	return result, interrupted(ctx, err)
+}
+
+// createCluster creates the cluster, filling result as it goes.
+func createCluster(ctx context.Context, opts Options, result *CreateResult, st *progress.Steps) (err error) {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	opts, err = opts.withDefaults()
+	if err != nil {
+		return clierror.New(clierror.ExitWorkspace, err)
+	}
+	log.Info().Str("cluster", opts.Name).Msg("Creating local k8s cluster...")
+	result.ClusterName = opts.Name
+	result.GitOps = opts.GitOps
+	disableGitOps := opts.GitOps == gitops.EngineNone
+	controller, err := ingress.Lookup(opts.Ingress)
+	if err != nil {
+		return clierror.New(clierror.ExitUsage, fmt.Errorf("invalid ingress type: %w", err))
+	}
+	repos, err := loadRemoteRepos(opts)
+	if err != nil {
+		return clierror.New(clierror.ExitUsage, err)
+	}
+
+	// locate or create kind config inside clusters/<name>
+	kindCfgPath := opts.KindConfig
+	if kindCfgPath == "" {
+		log.Info().Str("cluster", opts.Name).Msg("locating kind config")
+		kindCfgPath = FindKindConfig(opts.Directory, opts.Name)
+		log.Debug().Str("path", kindCfgPath).Msg("kind config path located")
+	}
+	var kindCfg *kindcfg.KindCluster
+	log.Info().Str("path", kindCfgPath).Msg("loading or creating kind config")
+	kindCfgPath, kindCfg = loadOrCreateKindConfig(opts.Directory, kindCfgPath, opts.Name)
+
+	// GitOps / local-argo setup, then point the workspace chart at the
+	// selected ingress controller and engine on the branch the engine will
+	// track
+	var repoPath string
+	var useMirrors bool
+	err = st.Run(ctx, "workspace", "Setting up the workspace", false, func() error {
+		log.Info().Str("path", kindCfgPath).Str("gitops", opts.GitOps).Msg("setting up the local-argo repo inside the nodes")
+		var err error
+		if repoPath, kindCfgPath, kindCfg, err = setupLocalArgo(ctx, opts, kindCfgPath, kindCfg); err != nil {
+			return err
+		}
+		if useMirrors, err = syncRepoMirrors(ctx, opts.Directory, repos, kindCfgPath, kindCfg); err != nil {
+			return err
+		}
+		log.Info().Str("path", kindCfgPath).Msg("kind config ready")
+		if disableGitOps {
+			return nil
+		}
+		if err := checkoutWorkspaceBranch(repoPath, opts.Branch); err != nil {
+			return err
+		}
+		configureWorkspaceValues(repoPath, controller, opts.GitOps, opts.Domain)
+		return nil
+	})
+	if err != nil {
+		return clierror.New(clierror.ExitWorkspace, err)
+	}
+
+	// confirmation
+	if opts.Confirm != nil && !opts.Confirm(opts.Name) {
+		return clierror.Newf(clierror.ExitAborted, "cluster creation aborted")
+	}
+
+	// create cluster
+	kubeconfigPath := KubeconfigPath(opts.Directory, opts.Name)
+	kindClient := kindsvc.NewClient(kubeconfigPath)
+	kindClient.Directory = opts.Directory
+	kindClient.Retry = opts.Retry
+	kubectlClient := kubectl.NewClient(&kubeconfigPath, nil)
+	kubectlClient.Retry = opts.Retry
+	// once kind starts creating the cluster, a failure leaves a (partial)
+	// cluster behind; an existing cluster of that name is never rolled back
+	created := false
+	if opts.CleanupOnFailure {
+		defer func() {
+			if err != nil && created {
+				result.RolledBack = rollbackCluster(ctx, st, kindClient, opts)
+			}
+		}()
+	}
+	if err := checkClusterAbsent(ctx, kindClient, opts.Name); err != nil {
+		return err
+	}
+	created = true
+	err = st.Run(ctx, "kind-cluster", "Creating kind cluster", false, func() error {
+		if err := kindClient.Create(ctx, opts.Name, kindCfgPath); err != nil {
+			return err
+		}
+		// kind names the context kind-<name>
+		return kubeconfig.Normalize(kubeconfigPath, opts.Name)
+	})
+	if err != nil {
+		return clierror.New(clierror.ExitCluster, fmt.Errorf("creating kind cluster: %w", err))
+	}
+	result.KubeconfigPath = kubeconfigPath
+	log.Info().Str("name", opts.Name).Msg("kind cluster created")
+
+	// merge its context into the kubeconfig of the user
+	if opts.MergeKubeconfig {
+		err = st.Run(ctx, "kubeconfig", "Merging the kubeconfig", true, func() error {
+			return mergeKubeconfig(kubeconfigPath, opts, result)
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		st.Skip("kubeconfig", "Merging the kubeconfig")
+	}
+
+	// start load balancer (warn-only steps only fail the creation when it is
+	// interrupted)
+	log.Info().Msg("starting local load balancer for LoadBalancer services")
+	err = st.Run(ctx, "load-balancer", "Starting local load balancer", true, func() error {
+		var err error
+		result.LoadBalancer.Started, err = startLocalLoadBalancer(ctx, kindClient, opts)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// wait for readiness
+	err = st.Run(ctx, "readiness", "Waiting for cluster to be ready", true, func() error {
+		return waitForClusterReadiness(ctx, kubeconfigPath, opts.Timeouts.ClusterReady, opts.Retry)
+	})
+	if err != nil {
+		return err
+	}
+
+	// load the workspace CA into the cluster when TLS is requested
+	if opts.TLS {
+		err = st.Run(ctx, "tls", "Setting up local TLS", false, func() error {
+			return setupLocalTLS(ctx, kubectlClient, opts.Directory, repoPath)
+		})
+		if err != nil {
+			return clierror.New(clierror.ExitTLS, err)
+		}
+		result.CACertPath = ca.NewClient(filepath.Join(opts.Directory, "ca")).CertPath()
+		log.Info().Str("ca", result.CACertPath).Msg("local TLS ready")
+	} else {
+		st.Skip("tls", "Setting up local TLS")
+	}
+
+	// install the GitOps engine if requested, then apply its bootstrap
+	// manifests
+	if disableGitOps {
+		log.Info().Msg("skipping GitOps engine installation as requested")
+		st.Skip("gitops-engine", "Installing the GitOps engine")
+		st.Skip("bootstrap", "Applying bootstrap manifests")
+	} else {
+		var engine gitops.Engine
+		err = st.Run(ctx, "gitops-engine", "Installing "+opts.GitOps, false, func() error {
+			var err error
+			engine, result.ArgoCDAdminPassword, err = installGitOpsEngine(ctx, opts, kubeconfigPath, controller, useMirrors)
+			return err
+		})
+		if err != nil {
+			return clierror.New(clierror.ExitGitOps, err)
+		}
+		log.Info().Str("engine", engine.Name()).Msg("GitOps engine installed")
+
+		err = st.Run(ctx, "bootstrap", "Applying bootstrap manifests", false, func() error {
+			return applyBootstrapManifests(ctx, kubectlClient, repoPath, opts.Branch, engine)
+		})
+		if err != nil {
+			return clierror.New(clierror.ExitGitOps, err)
+		}
+		log.Info().Msg("bootstrap manifests applied")
+		if len(repos) > 0 {
+			err = st.Run(ctx, "remote-repos", "Registering remote repositories", true, func() error {
+				return registerRemoteRepos(ctx, engine, repos)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// wait for the LoadBalancer Service of the selected ingress controller
+	// (namespace and label selector are derived from the ingress type unless
+	// overridden with IngressService / IngressSelector).
+	query := ingressQuery(opts, controller)
+	err = st.Run(ctx, "ingress", "Waiting for LoadBalancer service for ingress", true, func() error {
+		svc, err := waitForLoadBalancerService(ctx, kubectlClient, query, opts.Timeouts.LoadBalancerService, opts.Retry)
+		if err != nil {
+			return fmt.Errorf("did not find LoadBalancer service for ingress: %w", err)
+		}
+		result.LoadBalancer.IngressAddress = svc.ExternalAddress()
+		log.Info().Str("service", svc.Name).Str("namespace", svc.Namespace).Str("address", result.LoadBalancer.IngressAddress).Msg("found LoadBalancer service for ingress")
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// update the dnsmasq configuration
+	if opts.SkipDNS {
+		log.Info().Msg("skipping dnsmasq configuration as requested")
+		st.Skip("dns", "Updating dnsmasq configuration")
+	} else if result.LoadBalancer.IngressAddress == "" {
+		log.Warn().Msg("skipping dnsmasq configuration; no ingress address available")
+		st.Skip("dns", "Updating dnsmasq configuration")
+	} else {
+		err = st.Run(ctx, "dns", "Updating dnsmasq configuration", true, func() error {
+			ip, err := resolveIngressIP(ctx, result.LoadBalancer.IngressAddress)
+			if err == nil {
+				err = updateDnsmasqConfig(ctx, opts.Domain, ip)
+			}
+			if err != nil {
+				return fmt.Errorf("updating dnsmasq configuration: %w", err)
+			}
+			log.Info().Str("domain", opts.Domain).Str("ip", ip).Msg("updated dnsmasq configuration")
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// cluster infos
+	scheme := "http"
+	if result.CACertPath != "" {
+		scheme = "https"
+	}
+	if opts.GitOps == gitops.EngineArgoCD {
+		result.ArgoCDURL = scheme + "://argocd." + opts.Domain
+	}
+	result.HeadlampURL = scheme + "://headlamp." + opts.Domain
+	err = st.Run(ctx, "headlamp-token", "Creating Headlamp token", true, func() error {
+		token, err := kubectlClient.CreateToken(ctx, "headlamp", "monitoring")
+		if err != nil {
+			return fmt.Errorf("creating headlamp token: %w", err)
+		}
+		result.HeadlampToken = token
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Info().Msg("local k8s cluster creation process completed")
+	return nil
+}
@@ -0,0 +1,126 @@
+package localplane
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"localplane/config"
+	"localplane/utils/clierror"
+	kindsvc "localplane/utils/kind"
+	"localplane/utils/progress"
+	"localplane/utils/retry"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DestroyOptions configures DestroyCluster.
+type DestroyOptions struct {
+	// Name of the cluster to delete (required).
+	Name string
+	// Directory holds the clusters/<name> directories (the working directory
+	// when empty).
+	Directory string
+	// UserKubeconfig is the kubeconfig of the user the context of the
+	// cluster is removed from, when it was merged (the files of $KUBECONFIG,
+	// else ~/.kube/config, when empty).
+	UserKubeconfig string
+	// Timeouts bound the waits on the cluster (only ClusterDeleted is used);
+	// zero fields take their DefaultTimeouts value.
+	Timeouts config.TimeoutsConfig
+	// Retry is the backoff of the kind calls failing with a transient error,
+	// and of the polling of the waits.
+	Retry retry.Policy
+	// Reporter reports the progress of the steps; nil reports nothing.
+	Reporter progress.Reporter
+}
+
+// DestroyCluster deletes a kind cluster, stops its load balancer and removes
+// its kubeconfig, and its context from the kubeconfig of the user, as
+// `localplane cluster destroy` does. Its clusters/<name> directory
+// (local-argo repo, kind config) is kept. The result is returned even on
+// failure. Errors are classified with clierror; once ctx is done they are
+// clierror.ExitAborted.
+func DestroyCluster(ctx context.Context, opts DestroyOptions) (*DestroyResult, error) {
+	result := &DestroyResult{}
+	st := progress.NewSteps(opts.Reporter)
+	err := destroyCluster(ctx, opts, result, st)
+	result.Steps = st.List()
+	return result, interrupted(ctx, err)
+}
+
+// destroyCluster deletes the cluster, filling result as it goes.
+func destroyCluster(ctx context.Context, opts DestroyOptions, result *DestroyResult, st *progress.Steps) error {
+	clusterName := strings.TrimSpace(opts.Name)
+	if clusterName == "" {
+		return clierror.Newf(clierror.ExitUsage, "the name of the cluster to delete is required")
+	}
+	result.ClusterName = clusterName
+	dir := opts.Directory
+	if dir == "" {
+		var err error
+		if dir, err = os.Getwd(); err != nil {
+			return fmt.Errorf("determining the working directory: %w", err)
+		}
+	}
+	log.Info().Str("cluster", clusterName).Msg("Deleting local k8s cluster...")
+
+	// shutdown cluster
+	kindClient := kindsvc.NewClient("")
+	kindClient.Directory = dir
+	kindClient.Retry = opts.Retry
+	// kind deletes unknown clusters without complaining
+	names, err := kindClient.List(ctx)
+	if err != nil {
+		return clierror.New(clierror.ExitCluster, err)
+	}
+	if !slices.Contains(names, clusterName) {
+		return clierror.Newf(clierror.ExitNotFound, "no kind cluster named %s", clusterName)
+	}
+	err = st.Run(ctx, "kind-cluster", "Deleting kind cluster", false, func() error {
+		return kindClient.Delete(ctx, clusterName)
+	})
+	if err != nil {
+		return clierror.New(clierror.ExitCluster, fmt.Errorf("deleting kind cluster: %w", err))
+	}
+	log.Info().Str("name", clusterName).Msg("kind cluster deletion invoked")
+
+	// stop the load balancer provider if running (it may not have been)
+	_ = st.Run(ctx, "load-balancer", "Stopping local load balancer", true, func() error {
+		if err := kindClient.StopLoadBalancer(ctx, clusterName); err != nil {
+			return fmt.Errorf("stopping cloud-provider-kind load balancer: %w", err)
+		}
+		result.LoadBalancerStopped = true
+		log.Info().Str("name", clusterName).Msg("stopped cloud-provider-kind load balancer (if it was running)")
+		return nil
+	})
+
+	// make sure the cluster is stopped/deleted: poll the kind clusters briefly
+	timeout := withDefaultTimeouts(opts.Timeouts).ClusterDeleted
+	err = st.Run(ctx, "wait-deleted", "Waiting for the cluster to be removed", false, func() error {
+		if err := waitForClusterStopped(ctx, kindClient, clusterName, timeout, opts.Retry); err != nil {
+			return fmt.Errorf("%w; manual cleanup may be needed", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return clierror.New(clierror.ExitCluster, err)
+	}
+	result.Deleted = true
+
+	// cleanup local files
+	_ = st.Run(ctx, "cleanup", "Removing the kubeconfig", true, func() error {
+		var err error
+		if result.KubeconfigUnmerged, err = unmergeKubeconfig(opts.UserKubeconfig, clusterName); err != nil {
+			return err
+		}
+		if err := removeKubeconfig(dir, clusterName); err != nil {
+			return err
+		}
+		result.KubeconfigRemoved = true
+		return nil
+	})
+	return nil
+}
@@ -0,0 +1,18 @@
+// Package localplane creates and deletes localplane clusters from Go, for
+// tools and integration tests. It runs the workflow of the `localplane
+// cluster` commands without reading flags, configuration files or prompting:
+// everything comes from the options, and errors are returned, classified
+// with clierror so callers can tell a failed kind cluster from an invalid
+// option.
+//
+//	res, err := localplane.CreateCluster(ctx, localplane.Options{
+//		Name:      "it",
+//		Directory: dir,
+//		GitOps:    gitops.EngineNone,
+//		SkipDNS:   true,
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	defer localplane.DestroyCluster(ctx, localplane.DestroyOptions{Name: res.ClusterName, Directory: dir})
+package localplane
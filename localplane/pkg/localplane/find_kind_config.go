@@ -1,15 +1,16 @@
-package shared
+package localplane
 
 import (
 	"os"
 	"path/filepath"
-
-	"localplane/config"
 )
 
-// findKindConfig searches for common kind config filenames and returns the first found path or empty string.
-func FindKindConfig(clusterName string) string {
-	base := config.CliConfig.Directory
+// FindKindConfig searches for common kind config filenames under
+// `<dir>/clusters/<clusterName>`, dir, then the working directory, and
+// returns the first found path or empty string. An empty dir is the working
+// directory.
+func FindKindConfig(dir, clusterName string) string {
+	base := dir
 	var err error
 	if base == "" {
 		base, err = os.Getwd()
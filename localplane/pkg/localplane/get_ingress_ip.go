@@ -0,0 +1,111 @@
+package localplane
+
+import (
+	"context"
+	"fmt"
+	"localplane/utils/kubectl"
+	"localplane/utils/retry"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ingressServiceQuery describes how to find the LoadBalancer Service of the
+// ingress controller. Name takes precedence over Selector when both are set.
+type ingressServiceQuery struct {
+	Namespace string
+	Name      string
+	Selector  string
+}
+
+func (q ingressServiceQuery) String() string {
+	switch {
+	case q.Name != "":
+		return fmt.Sprintf("service %s/%s", q.Namespace, q.Name)
+	case q.Selector != "":
+		return fmt.Sprintf("services matching %q in namespace %s", q.Selector, q.Namespace)
+	default:
+		return fmt.Sprintf("services in namespace %s", q.Namespace)
+	}
+}
+
+// waitForLoadBalancerService polls, with the backoff of policy, for a
+// Service of type LoadBalancer matching the query until it has an external IP
+// or hostname, and returns it. When several services match, the first one (by
+// name) with an address wins. Returns an error describing what was last
+// observed on timeout.
+func waitForLoadBalancerService(ctx context.Context, c *kubectl.Client, query ingressServiceQuery, timeout time.Duration, policy retry.Policy) (*kubectl.Service, error) {
+	if query.Namespace == "" {
+		return nil, fmt.Errorf("namespace must be provided")
+	}
+
+	svcType := "LoadBalancer"
+	selector := query.Selector
+	if query.Name != "" {
+		selector = ""
+	}
+
+	var found *kubectl.Service
+	err := retry.Until(ctx, policy, timeout, func(ctx context.Context) (bool, error) {
+		svcs, err := c.ListServices(ctx, query.Namespace, &svcType, selector)
+		if err != nil {
+			// transient error; try again until timeout
+			return false, err
+		}
+		if query.Name != "" {
+			filtered := svcs[:0]
+			for _, s := range svcs {
+				if s.Name == query.Name {
+					filtered = append(filtered, s)
+				}
+			}
+			svcs = filtered
+		}
+		if len(svcs) == 0 {
+			return false, fmt.Errorf("no LoadBalancer service found")
+		}
+
+		sort.Slice(svcs, func(i, j int) bool { return svcs[i].Name < svcs[j].Name })
+		names := make([]string, 0, len(svcs))
+		for _, s := range svcs {
+			names = append(names, s.Name)
+		}
+		for i := range svcs {
+			if svcs[i].ExternalAddress() != "" {
+				if len(svcs) > 1 {
+					log.Warn().Strs("services", names).Str("selected", svcs[i].Name).Msg("several LoadBalancer services match; use --ingress-service to pick one explicitly")
+				}
+				found = &svcs[i]
+				return true, nil
+			}
+		}
+		return false, fmt.Errorf("no external address assigned yet to %s", strings.Join(names, ", "))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("waiting for %s: %w", query, err)
+	}
+	return found, nil
+}
+
+// resolveIngressIP returns address when it is already an IP, otherwise it
+// resolves the LoadBalancer hostname to its first IP since dnsmasq `address=`
+// entries only accept IPs.
+func resolveIngressIP(ctx context.Context, address string) (string, error) {
+	if address == "" {
+		return "", fmt.Errorf("no ingress address")
+	}
+	if net.ParseIP(address) != nil {
+		return address, nil
+	}
+	ips, err := net.DefaultResolver.LookupHost(ctx, address)
+	if err != nil {
+		return "", fmt.Errorf("resolving ingress hostname %s: %w", address, err)
+	}
+	if len(ips) == 0 {
+		return "", fmt.Errorf("ingress hostname %s resolved to no addresses", address)
+	}
+	return ips[0], nil
+}
@@ -0,0 +1,29 @@
+package localplane
+
+import (
+	"strings"
+
+	"localplane/utils/ingress"
+)
+
+// ingressQuery builds the ingress Service lookup from the selected
+// controller, applying the IngressService and IngressSelector overrides of o.
+func ingressQuery(o Options, controller ingress.Controller) ingressServiceQuery {
+	query := ingressServiceQuery{
+		Namespace: controller.Namespace,
+		Selector:  controller.ServiceSelector,
+	}
+
+	if selector := strings.TrimSpace(o.IngressSelector); selector != "" {
+		query.Selector = selector
+	}
+
+	if name := strings.TrimSpace(o.IngressService); name != "" {
+		if ns, n, ok := strings.Cut(name, "/"); ok {
+			query.Namespace = ns
+			name = n
+		}
+		query.Name = name
+	}
+	return query
+}
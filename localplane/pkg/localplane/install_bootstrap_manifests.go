@@ -0,0 +1,36 @@
+package localplane
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+
+	gitutil "localplane/utils/git"
+	"localplane/utils/gitops"
+	"localplane/utils/kubectl"
+)
+
+// applyBootstrapManifests applies the bootstrap manifests of the GitOps engine
+// from the cluster's local-argo chart into the created cluster and points the
+// engine at branch. The branch must have at least one commit, otherwise the
+// engine would never find the revision.
+func applyBootstrapManifests(ctx context.Context, kubectlClient *kubectl.Client, repoPath string, branch string, engine gitops.Engine) error {
+	if !gitutil.NewClient(repoPath).HasCommit("refs/heads/" + branch) {
+		return fmt.Errorf("branch %s of the local-argo repo has no commit; commit the workspace chart before bootstrapping", branch)
+	}
+
+	bootstrapPath := filepath.Join(repoPath, "charts", "workspace", "bootstrap")
+	patterns := engine.BootstrapPatterns(bootstrapPath)
+	log.Info().Strs("patterns", patterns).Msg("applying bootstrap manifests into cluster")
+	if err := kubectlClient.ApplyPaths(ctx, patterns); err != nil {
+		return fmt.Errorf("applying bootstrap manifests: %w", err)
+	}
+	log.Info().Msg("applied bootstrap manifests into cluster")
+	if err := engine.TrackBranch(ctx, branch); err != nil {
+		return fmt.Errorf("pointing the GitOps engine at branch %s: %w", branch, err)
+	}
+	log.Info().Str("branch", branch).Str("engine", engine.Name()).Msg("GitOps engine tracks local-argo branch")
+	return nil
+}
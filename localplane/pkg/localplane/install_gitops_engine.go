@@ -0,0 +1,92 @@
+package localplane
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+
+	argocdsvc "localplane/utils/argocd"
+	"localplane/utils/ca"
+	"localplane/utils/flux"
+	"localplane/utils/gitops"
+	"localplane/utils/ingress"
+	"localplane/utils/remoterepo"
+)
+
+// installGitOpsEngine installs or upgrades the GitOps engine of o via Helm.
+// For ArgoCD in secure mode the admin password stored in the cluster
+// directory (generated on first use) is applied and returned. useMirrors
+// mounts the remote repository mirrors into the ArgoCD repo-server.
+func installGitOpsEngine(ctx context.Context, o Options, kubeconfigPath string, controller ingress.Controller, useMirrors bool) (gitops.Engine, string, error) {
+	opts := gitops.Options{Kubeconfig: kubeconfigPath, Retry: o.Retry}
+	if o.GitOps == gitops.EngineArgoCD {
+		var err error
+		opts.ArgoCDMounts, opts.ArgoCDInstall, err = argoCDInstallOptions(o, controller, useMirrors)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	if o.GitOps == gitops.EngineFlux {
+		opts.FluxInstall = flux.InstallOptions{ChartVersion: o.Flux.ChartVersion, ChartPath: o.Flux.ChartPath, Timeout: o.Timeouts.HelmInstall}
+	}
+
+	engine, err := gitops.New(o.GitOps, opts)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid gitops engine: %w", err)
+	}
+	out, err := engine.Install(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("installing %s via helm sdk: %w", engine.Name(), err)
+	}
+	log.Info().Str("output", out).Str("engine", engine.Name()).Msg("gitops engine installed")
+	return engine, opts.ArgoCDInstall.AdminPassword, nil
+}
+
+// argoCDInstallOptions builds the ArgoCD mounts and install options from o.
+func argoCDInstallOptions(o Options, controller ingress.Controller, useMirrors bool) ([]argocdsvc.RepoMount, argocdsvc.InstallOptions, error) {
+	mounts := []argocdsvc.RepoMount{{
+		Name:      "local-argo",
+		HostPath:  "/mnt/local-argo",
+		MountPath: "/mnt/local-argo",
+	}}
+	if useMirrors {
+		mounts = append(mounts, argocdsvc.RepoMount{
+			Name:      "mirrors",
+			HostPath:  remoterepo.MirrorsMountPath,
+			MountPath: remoterepo.MirrorsMountPath,
+		})
+	}
+	opts := argocdsvc.InstallOptions{
+		ChartVersion: o.ArgoCD.ChartVersion,
+		ChartPath:    o.ArgoCD.ChartPath,
+		ValuesFiles:  o.ArgoCD.ValuesFiles,
+		Ingress:      controller,
+		Domain:       o.Domain,
+		Timeout:      o.Timeouts.HelmInstall,
+	}
+	if o.TLS {
+		opts.TLS = true
+		opts.ClusterIssuer = ca.ClusterIssuerName
+	}
+	if o.ArgoCD.Secure {
+		clusterDir := filepath.Join(o.Directory, "clusters", o.Name)
+		password, err := argocdsvc.LoadAdminPassword(clusterDir)
+		if err != nil {
+			return nil, opts, fmt.Errorf("reading argocd admin password: %w", err)
+		}
+		if password == "" {
+			if password, err = argocdsvc.GeneratePassword(20); err != nil {
+				return nil, opts, fmt.Errorf("generating argocd admin password: %w", err)
+			}
+			if err := argocdsvc.SaveAdminPassword(clusterDir, password); err != nil {
+				return nil, opts, fmt.Errorf("storing argocd admin password: %w", err)
+			}
+			log.Info().Str("path", argocdsvc.AdminPasswordPath(clusterDir)).Msg("generated argocd admin password")
+		}
+		opts.Secure = true
+		opts.AdminPassword = password
+	}
+	return mounts, opts, nil
+}
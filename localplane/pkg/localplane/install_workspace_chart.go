@@ -0,0 +1,61 @@
+package localplane
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"localplane/utils/templatesource"
+
+	"github.com/rs/zerolog/log"
+)
+
+// workspaceTemplateSource resolves the template source of o, with
+// TemplateRef overriding the ref or version of the source. It reports
+// whether the built-in chart may be used when fetching fails: only for the
+// localplane template itself.
+func workspaceTemplateSource(o Options) (templatesource.Source, bool, error) {
+	src, err := templatesource.Parse(o.Template)
+	if err != nil || o.TemplateRef == "" {
+		return src, false, err
+	}
+	fallback := src.Kind == templatesource.KindBuiltin
+	src, err = src.WithRef(o.TemplateRef)
+	if err != nil {
+		return src, false, fmt.Errorf("template ref: %w", err)
+	}
+	return src, fallback, nil
+}
+
+// installWorkspaceChart writes the workspace chart to dest from the
+// template source of o (the chart built into the binary by default) and
+// returns the source it was written from. Downloads are cached under `cache`
+// in the directory of o.
+func installWorkspaceChart(ctx context.Context, o Options, dest string) (templatesource.Source, error) {
+	src, fallback, err := workspaceTemplateSource(o)
+	if err != nil {
+		return src, fmt.Errorf("invalid workspace template source: %w", err)
+	}
+
+	client := templatesource.NewClient(filepath.Join(o.Directory, "cache"))
+	client.Retry = o.Retry
+	if src.Kind != templatesource.KindBuiltin {
+		log.Info().Str("path", dest).Str("source", src.String()).Msg("fetching workspace helm chart")
+		err := client.Fetch(ctx, src, dest)
+		if err == nil {
+			log.Info().Str("path", dest).Str("source", src.String()).Msg("fetched workspace helm chart into local-argo repo")
+			return src, nil
+		}
+		if !fallback {
+			return src, fmt.Errorf("fetching workspace helm chart from %s: %w", src, err)
+		}
+		log.Warn().Err(err).Str("source", src.String()).Msg("failed to fetch workspace helm chart; using the chart built into localplane")
+	}
+
+	builtin := templatesource.Source{Kind: templatesource.KindBuiltin}
+	if err := client.Fetch(ctx, builtin, dest); err != nil {
+		return builtin, fmt.Errorf("writing workspace helm chart: %w", err)
+	}
+	log.Info().Str("path", dest).Msg("wrote built-in workspace helm chart into local-argo repo")
+	return builtin, nil
+}
@@ -0,0 +1,17 @@
+package localplane
+
+import (
+	"context"
+	"fmt"
+
+	"localplane/utils/clierror"
+)
+
+// interrupted classifies err as clierror.ExitAborted when ctx is done, since
+// the failure then comes from the interruption rather than the step.
+func interrupted(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() == nil {
+		return err
+	}
+	return clierror.New(clierror.ExitAborted, fmt.Errorf("interrupted: %w", err))
+}
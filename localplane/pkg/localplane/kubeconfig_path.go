@@ -0,0 +1,9 @@
+package localplane
+
+import "path/filepath"
+
+// KubeconfigPath returns the kubeconfig written for a cluster,
+// `<base>/clusters/<clusterName>/kubeconfig`.
+func KubeconfigPath(base, clusterName string) string {
+	return filepath.Join(base, "clusters", clusterName, "kubeconfig")
+}
@@ -0,0 +1,41 @@
+package localplane
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"localplane/utils/clierror"
+	kindsvc "localplane/utils/kind"
+)
+
+// ListOptions configures ListClusters.
+type ListOptions struct {
+	// Directory holds the clusters/<name> directories (the working directory
+	// when empty).
+	Directory string
+}
+
+// ListClusters returns the existing kind clusters, telling which ones were
+// created from the directory of opts. Errors are classified with clierror.
+func ListClusters(ctx context.Context, opts ListOptions) ([]Cluster, error) {
+	names, err := kindsvc.NewClient("").List(ctx)
+	if err != nil {
+		return nil, clierror.New(clierror.ExitCluster, err)
+	}
+	clusters := make([]Cluster, 0, len(names))
+	for _, name := range names {
+		c := Cluster{Name: name, Managed: isDir(filepath.Join(opts.Directory, "clusters", name))}
+		if kubeconfig := KubeconfigPath(opts.Directory, name); fileExists(kubeconfig) {
+			c.KubeconfigPath = kubeconfig
+		}
+		clusters = append(clusters, c)
+	}
+	return clusters, nil
+}
+
+// fileExists reports whether path exists and is a regular file.
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
@@ -0,0 +1,24 @@
+package localplane
+
+import (
+	"errors"
+	"io/fs"
+
+	"localplane/utils/clierror"
+	"localplane/utils/kubeconfig"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// LoadClusterKubeconfig reads the kubeconfig written for a cluster (see
+// KubeconfigPath), its context, cluster and user named
+// `localplane-<clusterName>`. A missing kubeconfig is a clierror.ExitNotFound
+// error.
+func LoadClusterKubeconfig(base, clusterName string) (*api.Config, error) {
+	path := KubeconfigPath(base, clusterName)
+	cfg, err := kubeconfig.Load(path, clusterName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, clierror.Newf(clierror.ExitNotFound, "no kubeconfig for cluster %s in %s; was it created in this workspace?", clusterName, base)
+	}
+	return cfg, err
+}
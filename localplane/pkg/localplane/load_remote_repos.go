@@ -0,0 +1,37 @@
+package localplane
+
+import (
+	"fmt"
+
+	"localplane/utils/flux"
+	"localplane/utils/gitops"
+	"localplane/utils/remoterepo"
+
+	"github.com/rs/zerolog/log"
+)
+
+// loadRemoteRepos resolves the remote repositories of o, with the branch
+// overrides, and checks the selected engine supports them before anything
+// is created.
+func loadRemoteRepos(o Options) ([]remoterepo.Repository, error) {
+	repos, err := remoterepo.Load(o.Repositories, o.RepoBranches)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote repository configuration: %w", err)
+	}
+	if len(repos) == 0 {
+		return nil, nil
+	}
+	switch o.GitOps {
+	case gitops.EngineNone:
+		log.Warn().Int("repositories", len(repos)).Msg("GitOps disabled; ignoring configured remote repositories")
+		return nil, nil
+	case gitops.EngineFlux:
+		if _, err := flux.RepositoryManifests(repos); err != nil {
+			return nil, fmt.Errorf("remote repositories not supported by flux: %w", err)
+		}
+	}
+	for _, repo := range repos {
+		log.Info().Str("name", repo.Name).Str("url", repo.URL).Str("branch", repo.Branch).Bool("mirror", repo.Mirror).Msg("remote repository configured")
+	}
+	return repos, nil
+}
@@ -0,0 +1,10 @@
+package localplane
+
+import "path/filepath"
+
+// LocalArgoRepoPath returns the GitOps repo of a cluster,
+// `<base>/clusters/<clusterName>/local-argo`. Each cluster gets its own repo
+// so workspace values (ingress, TLS, engine) don't leak between clusters.
+func LocalArgoRepoPath(base, clusterName string) string {
+	return filepath.Join(base, "clusters", clusterName, "local-argo")
+}
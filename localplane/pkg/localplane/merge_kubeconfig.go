@@ -0,0 +1,25 @@
+package localplane
+
+import (
+	"fmt"
+
+	"localplane/utils/kubeconfig"
+
+	"github.com/rs/zerolog/log"
+)
+
+// mergeKubeconfig merges the context of the cluster of kubeconfigPath into
+// the kubeconfig of the user and makes it the current one.
+func mergeKubeconfig(kubeconfigPath string, opts Options, result *CreateResult) error {
+	cfg, err := kubeconfig.Load(kubeconfigPath, opts.Name)
+	if err != nil {
+		return err
+	}
+	client := kubeconfig.NewClient(opts.UserKubeconfig)
+	if err := client.Merge(cfg, true); err != nil {
+		return fmt.Errorf("merging context %s into the kubeconfig: %w", cfg.CurrentContext, err)
+	}
+	result.KubeContext = cfg.CurrentContext
+	log.Info().Str("context", cfg.CurrentContext).Str("kubeconfig", client.Filename()).Msg("merged the cluster context into the kubeconfig")
+	return nil
+}
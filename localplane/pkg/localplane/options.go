@@ -0,0 +1,170 @@
+package localplane
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"localplane/config"
+	"localplane/utils/clierror"
+	gitutil "localplane/utils/git"
+	"localplane/utils/gitops"
+	"localplane/utils/ingress"
+	"localplane/utils/progress"
+	"localplane/utils/retry"
+)
+
+// DefaultClusterName is the name of the cluster when none is given.
+const DefaultClusterName = config.DefaultClusterName
+
+// DefaultDomain is the DNS domain of the hostnames served by the clusters
+// when none is given.
+const DefaultDomain = config.DefaultDomain
+
+// Options configures CreateCluster. The zero value creates the cluster
+// `localplane cluster create` creates without flags.
+type Options struct {
+	// Name of the cluster (DefaultClusterName when empty).
+	Name string
+	// Directory holds the clusters/<name> directories, the workspace CA and
+	// the caches (the working directory when empty).
+	Directory string
+	// KindConfig is the kind config of the cluster (searched with
+	// FindKindConfig when empty, a default one is written when none is
+	// found).
+	KindConfig string
+	// Domain is the DNS domain of the hostnames served by the cluster
+	// (DefaultDomain when empty): argocd.<domain>, headlamp.<domain>...
+	Domain string
+
+	// GitOps is the engine reconciling the local-argo repo
+	// (gitops.DefaultEngine when empty); gitops.EngineNone skips the GitOps
+	// setup.
+	GitOps string
+	// Branch of the local-argo repo tracked by the engine
+	// (gitutil.DefaultBranch when empty).
+	Branch string
+	// Template is the source of the workspace chart (see
+	// templatesource.Parse), the chart built into localplane when empty.
+	// TemplateRef overrides its ref or version.
+	Template    string
+	TemplateRef string
+	// Repositories are remote git repositories registered with the engine.
+	// RepoBranches overrides their tracked branch by name.
+	Repositories []config.RepositoryConfig
+	RepoBranches map[string]string
+	ArgoCD       ArgoCDOptions
+	Flux         FluxOptions
+
+	// Ingress is the ingress controller type (ingress.DefaultType when
+	// empty). IngressService (`<name>` or `<namespace>/<name>`) and
+	// IngressSelector override how its LoadBalancer service is found.
+	Ingress         string
+	IngressService  string
+	IngressSelector string
+	// TLS serves the localplane hostnames over HTTPS with the workspace CA.
+	TLS bool
+
+	// SkipLoadBalancer doesn't start cloud-provider-kind.
+	SkipLoadBalancer bool
+	// LoadBalancerForeground runs cloud-provider-kind in the foreground,
+	// blocking until it exits.
+	LoadBalancerForeground bool
+	// SkipDNS doesn't update the dnsmasq configuration.
+	SkipDNS bool
+	// Timeouts bound the waits on the cluster; zero fields take their
+	// DefaultTimeouts value.
+	Timeouts config.TimeoutsConfig
+	// Retry is the backoff of the kind, kubectl and GitHub calls failing with
+	// a transient error, and of the polling of the waits.
+	Retry retry.Policy
+
+	// MergeKubeconfig merges the context of the cluster, named
+	// `localplane-<name>`, into UserKubeconfig and makes it the current one.
+	MergeKubeconfig bool
+	// UserKubeconfig is the kubeconfig of the user (the files of $KUBECONFIG,
+	// else ~/.kube/config, when empty).
+	UserKubeconfig string
+
+	// CleanupOnFailure deletes the kind cluster, its load balancer and its
+	// kubeconfig when the creation fails or is interrupted after the cluster
+	// was started. The clusters/<name> directory is kept.
+	CleanupOnFailure bool
+
+	// Confirm is called once the workspace is ready, before the kind cluster
+	// is created; returning false aborts the creation. Nil proceeds.
+	Confirm func(clusterName string) bool
+	// Reporter reports the progress of the steps; nil reports nothing.
+	Reporter progress.Reporter
+}
+
+// ArgoCDOptions configures the ArgoCD install.
+type ArgoCDOptions struct {
+	// ChartVersion of the argo-cd chart (argocd.DefaultChartVersion when
+	// empty); ChartPath installs a local chart instead.
+	ChartVersion string
+	ChartPath    string
+	// ValuesFiles are merged on top of the built-in values.
+	ValuesFiles []string
+	// Secure disables anonymous access and protects the admin account with
+	// a generated password.
+	Secure bool
+}
+
+// FluxOptions configures the Flux install.
+type FluxOptions struct {
+	// ChartVersion of the flux2 chart (flux.DefaultChartVersion when
+	// empty); ChartPath installs a local chart instead.
+	ChartVersion string
+	ChartPath    string
+}
+
+// Validate checks the options without side effects. Errors are classified
+// with clierror.ExitUsage.
+func (o Options) Validate() error {
+	if _, err := gitops.New(o.GitOps, gitops.Options{}); err != nil {
+		return clierror.New(clierror.ExitUsage, fmt.Errorf("invalid gitops engine: %w", err))
+	}
+	controller, err := ingress.Lookup(o.Ingress)
+	if err != nil {
+		return clierror.New(clierror.ExitUsage, fmt.Errorf("invalid ingress type: %w", err))
+	}
+	// the Flux flavour of the addons chart can't order CRD dependent
+	// resources (ClusterIssuer, Gateway) after their controllers yet
+	if strings.EqualFold(strings.TrimSpace(o.GitOps), gitops.EngineFlux) && (o.TLS || controller.UsesGatewayAPI()) {
+		return clierror.Newf(clierror.ExitUsage, "TLS and the gateway-api ingress are not supported with the flux gitops engine yet")
+	}
+	if _, _, err := workspaceTemplateSource(o); err != nil {
+		return clierror.New(clierror.ExitUsage, fmt.Errorf("invalid workspace template source: %w", err))
+	}
+	return nil
+}
+
+// withDefaults returns o with the defaults of the empty fields.
+func (o Options) withDefaults() (Options, error) {
+	if strings.TrimSpace(o.Name) == "" {
+		o.Name = DefaultClusterName
+	}
+	if o.Directory == "" {
+		dir, err := os.Getwd()
+		if err != nil {
+			return o, fmt.Errorf("determining the working directory: %w", err)
+		}
+		o.Directory = dir
+	}
+	if strings.TrimSpace(o.Domain) == "" {
+		o.Domain = DefaultDomain
+	}
+	o.GitOps = strings.ToLower(strings.TrimSpace(o.GitOps))
+	if o.GitOps == "" {
+		o.GitOps = gitops.DefaultEngine
+	}
+	if strings.TrimSpace(o.Branch) == "" {
+		o.Branch = gitutil.DefaultBranch
+	}
+	if o.Ingress == "" {
+		o.Ingress = ingress.DefaultType
+	}
+	o.Timeouts = withDefaultTimeouts(o.Timeouts)
+	return o, nil
+}
@@ -0,0 +1,27 @@
+package localplane
+
+import (
+	"context"
+	"fmt"
+
+	"localplane/utils/gitops"
+	"localplane/utils/remoterepo"
+
+	"github.com/rs/zerolog/log"
+)
+
+// registerRemoteRepos registers the remote repositories with the GitOps
+// engine. Callers only warn on failure: the cluster and the local-argo flow
+// still work without them.
+func registerRemoteRepos(ctx context.Context, engine gitops.Engine, repos []remoterepo.Repository) error {
+	if engine == nil || len(repos) == 0 {
+		return nil
+	}
+	if err := engine.RegisterRepositories(ctx, repos); err != nil {
+		return fmt.Errorf("registering remote repositories with %s: %w", engine.Name(), err)
+	}
+	for _, repo := range repos {
+		log.Info().Str("name", repo.Name).Str("url", repo.ClusterURL()).Str("branch", repo.Branch).Str("path", repo.Path).Msg("registered remote repository")
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package localplane
+
+import "localplane/utils/output"
+
+// CreateResult describes a cluster created by CreateCluster. Optional fields
+// are left empty when the matching feature is disabled.
+type CreateResult struct {
+	ClusterName    string `json:"clusterName"`
+	KubeconfigPath string `json:"kubeconfigPath,omitempty"`
+	// KubeContext is the context merged into the kubeconfig of the user (see
+	// Options.MergeKubeconfig).
+	KubeContext string `json:"kubeContext,omitempty"`
+	GitOps      string `json:"gitops,omitempty"`
+	// ArgoCDURL is empty unless ArgoCD is the GitOps engine.
+	ArgoCDURL     string `json:"argocdUrl,omitempty"`
+	HeadlampURL   string `json:"headlampUrl,omitempty"`
+	HeadlampToken string `json:"headlampToken,omitempty"`
+	// CACertPath is set when the cluster serves its hostnames over HTTPS.
+	CACertPath string `json:"caCertPath,omitempty"`
+	// ArgoCDAdminPassword is set when ArgoCD runs in secure mode.
+	ArgoCDAdminPassword string            `json:"argocdAdminPassword,omitempty"`
+	LoadBalancer        LoadBalancerState `json:"loadBalancer"`
+	// RolledBack reports that a failed creation deleted the cluster (see
+	// Options.CleanupOnFailure).
+	RolledBack bool          `json:"rolledBack,omitempty"`
+	Steps      []output.Step `json:"steps"`
+}
+
+// LoadBalancerState describes the local load balancer of a cluster.
+type LoadBalancerState struct {
+	Started bool `json:"started"`
+	// IngressAddress is the external address of the ingress LoadBalancer
+	// service, empty when none was found.
+	IngressAddress string `json:"ingressAddress,omitempty"`
+}
+
+// DestroyResult describes what DestroyCluster removed.
+type DestroyResult struct {
+	ClusterName         string `json:"clusterName,omitempty"`
+	Deleted             bool   `json:"deleted"`
+	LoadBalancerStopped bool   `json:"loadBalancerStopped"`
+	KubeconfigRemoved   bool   `json:"kubeconfigRemoved"`
+	// KubeconfigUnmerged reports that the context of the cluster was removed
+	// from the kubeconfig of the user.
+	KubeconfigUnmerged bool          `json:"kubeconfigUnmerged"`
+	Steps              []output.Step `json:"steps"`
+}
+
+// Cluster is a kind cluster listed by ListClusters.
+type Cluster struct {
+	Name string `json:"name"`
+	// KubeconfigPath is the kubeconfig localplane wrote for the cluster,
+	// empty when there is none in the directory.
+	KubeconfigPath string `json:"kubeconfigPath,omitempty"`
+	// Managed reports whether the directory has a `clusters/<name>`
+	// directory for the cluster, i.e. it was created from this directory.
+	Managed bool `json:"managed"`
+}
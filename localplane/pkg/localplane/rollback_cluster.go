@@ -0,0 +1,47 @@
+package localplane
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"time"
+
+	kindsvc "localplane/utils/kind"
+	"localplane/utils/progress"
+
+	"github.com/rs/zerolog/log"
+)
+
+// rollbackTimeout bounds the cleanup of a failed creation.
+const rollbackTimeout = 2 * time.Minute
+
+// rollbackCluster deletes what a failed creation left behind: the load
+// balancer, the kind cluster and its kubeconfig, merged or not. It runs on a
+// context of its own so it also runs once ctx is cancelled. It reports
+// whether the cluster was deleted.
+func rollbackCluster(ctx context.Context, st *progress.Steps, kindClient *kindsvc.Client, opts Options) bool {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), rollbackTimeout)
+	defer cancel()
+	log.Warn().Str("cluster", opts.Name).Msg("cluster creation failed; deleting the partially created cluster")
+
+	deleted := false
+	_ = st.Run(ctx, "rollback", "Deleting the partially created cluster", true, func() error {
+		if err := kindClient.StopLoadBalancer(ctx, opts.Name); err != nil {
+			log.Warn().Err(err).Msg("failed to stop the cloud-provider-kind load balancer")
+		}
+		if err := kindClient.Delete(ctx, opts.Name); err != nil {
+			return err
+		}
+		deleted = true
+		if opts.MergeKubeconfig {
+			if _, err := unmergeKubeconfig(opts.UserKubeconfig, opts.Name); err != nil {
+				return err
+			}
+		}
+		if err := removeKubeconfig(opts.Directory, opts.Name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	})
+	return deleted
+}
@@ -0,0 +1,106 @@
+package localplane
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	gitutil "localplane/utils/git"
+	"localplane/utils/gitops"
+	kindcfg "localplane/utils/kind/config"
+	"localplane/utils/templatesource"
+
+	"github.com/rs/zerolog/log"
+)
+
+// setupLocalArgo performs creation of the cluster's local-argo git repo under
+// `clusters/<clusterName>/local-argo`, patches the kind config with a mount,
+// and writes the workspace chart into it if missing. A kind config shared by
+// several clusters is left untouched: the patched copy is written to the
+// cluster directory instead. It returns the repo path (empty when GitOps is
+// disabled), possibly-updated kindCfgPath and kindCfg. It fails when the
+// workspace chart can't be written.
+func setupLocalArgo(ctx context.Context, o Options, kindCfgPath string, kindCfg *kindcfg.KindCluster) (string, string, *kindcfg.KindCluster, error) {
+	base, clusterName := o.Directory, o.Name
+	if o.GitOps == gitops.EngineNone {
+		log.Info().Msg("GitOps setup disabled; skipping local-argo related tasks")
+		return "", kindCfgPath, kindCfg, nil
+	}
+
+	repoPath := LocalArgoRepoPath(base, clusterName)
+	if legacyPath := filepath.Join(base, "local-argo"); isDir(legacyPath) {
+		log.Warn().Str("path", legacyPath).Str("repo", repoPath).Msg("the shared local-argo repo is no longer used; each cluster now has its own repo (copy your changes over if needed)")
+	}
+
+	gitClient := gitutil.NewClient(repoPath)
+	if gitClient.IsRepository() {
+		log.Info().Str("path", repoPath).Msg("reusing existing local-argo git repo")
+	} else {
+		log.Debug().Str("path", repoPath).Msg("initializing local-argo git repo")
+		if err := gitClient.InitializeGitRepo(); err != nil {
+			log.Error().Err(err).Str("path", repoPath).Msg("failed to create local-argo git repo")
+		} else {
+			log.Info().Str("path", repoPath).Msg("created local-argo git repo")
+		}
+	}
+
+	// add mount to kind config for the cluster's local-argo repo
+	if kindCfgPath != "" {
+		containerPath := "/mnt/local-argo"
+		if kindCfg == nil {
+			if cfg, err := kindcfg.LoadKindConfig(kindCfgPath); err != nil {
+				log.Debug().Err(err).Str("path", kindCfgPath).Msg("failed to reload kind config before adding mount")
+			} else {
+				kindCfg = cfg
+			}
+		}
+		if kindCfg != nil {
+			clusterDir := filepath.Join(base, "clusters", clusterName)
+			if filepath.Dir(kindCfgPath) != clusterDir {
+				log.Info().Str("shared", kindCfgPath).Str("cluster", clusterName).Msg("writing cluster specific copy of shared kind config")
+				kindCfgPath = filepath.Join(clusterDir, "kind-config.yaml")
+			}
+			kindcfg.AddExtraMount(kindCfg, repoPath, containerPath)
+			if err := os.MkdirAll(clusterDir, 0o755); err != nil {
+				log.Error().Err(err).Str("path", clusterDir).Msg("failed to create cluster directory")
+			} else if err := kindcfg.SaveKindConfig(kindCfgPath, kindCfg); err != nil {
+				log.Error().Err(err).Str("path", kindCfgPath).Msg("failed to write updated kind config with local-argo mount")
+			} else {
+				log.Info().Str("hostPath", repoPath).Str("containerPath", containerPath).Msg("added local-argo mount to kind config")
+			}
+		} else {
+			log.Debug().Msg("no kind config available to patch with local-argo mount")
+		}
+	}
+
+	// write the workspace helm chart into local-argo if missing
+	localStackPath := filepath.Join(repoPath, "charts", "workspace")
+	log.Debug().Str("path", localStackPath).Msg("checking for workspace helm chart in local-argo repo")
+	if _, err := os.Stat(localStackPath); os.IsNotExist(err) {
+		src, err := installWorkspaceChart(ctx, o, localStackPath)
+		if err != nil {
+			return repoPath, kindCfgPath, kindCfg, err
+		}
+		// keep a pristine copy of the template for `workspace upgrade`
+		if err := templatesource.SaveInstalled(TemplateStatePath(base, clusterName), src, localStackPath); err != nil {
+			log.Warn().Err(err).Msg("failed to record the installed workspace template; upgrades won't be able to merge local changes")
+		}
+
+		// commit local-argo repo changes
+		if err := gitClient.CommitAll("Update local-argo repo with local-stack helm chart"); err != nil {
+			log.Error().Err(err).Str("path", repoPath).Msg("failed to commit changes to local-argo git repo")
+		} else {
+			log.Info().Str("path", repoPath).Msg("committed changes to local-argo git repo")
+		}
+	} else {
+		log.Info().Str("path", localStackPath).Msg("local-stack helm chart already exists; skipping download")
+	}
+
+	return repoPath, kindCfgPath, kindCfg, nil
+}
+
+// isDir reports whether path exists and is a directory.
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
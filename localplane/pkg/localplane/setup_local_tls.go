@@ -0,0 +1,52 @@
+package localplane
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"localplane/utils/ca"
+	gitutil "localplane/utils/git"
+	"localplane/utils/helmvalues"
+	"localplane/utils/kubectl"
+
+	"github.com/rs/zerolog/log"
+)
+
+// setupLocalTLS makes sure the workspace CA exists, loads it into the cluster
+// as the secret backing the cert-manager ClusterIssuer and turns TLS on in the
+// workspace values of the cluster's local-argo repo (repoPath, empty when
+// GitOps is disabled). It fails when the CA can't be created or loaded, since
+// every https URL would be broken.
+func setupLocalTLS(ctx context.Context, kubectlClient *kubectl.Client, base, repoPath string) error {
+	caClient := ca.NewClient(filepath.Join(base, "ca"))
+	if err := caClient.EnsureCA(); err != nil {
+		return fmt.Errorf("creating workspace CA: %w", err)
+	}
+
+	manifest, err := caClient.SecretManifest()
+	if err != nil {
+		return fmt.Errorf("rendering workspace CA secret: %w", err)
+	}
+	if err := kubectlClient.ApplyManifest(ctx, manifest); err != nil {
+		return fmt.Errorf("loading workspace CA into cluster: %w", err)
+	}
+	log.Info().Str("secret", ca.SecretName).Str("namespace", ca.SecretNamespace).Msg("loaded workspace CA into cluster")
+
+	if repoPath == "" {
+		return nil
+	}
+
+	valuesPath := filepath.Join(repoPath, "charts", "workspace", "values", "localplane-addons.values.yaml")
+	if err := helmvalues.Set(valuesPath, "localplane-addons.tls.enabled", true); err != nil {
+		log.Error().Err(err).Str("path", valuesPath).Msg("failed to enable TLS in workspace values")
+		return nil
+	}
+	gitClient := gitutil.NewClient(repoPath)
+	if err := gitClient.CommitAll("Enable TLS for localplane addons"); err != nil {
+		log.Debug().Err(err).Str("path", repoPath).Msg("nothing committed while enabling TLS (already enabled?)")
+	} else {
+		log.Info().Str("path", valuesPath).Msg("enabled TLS in workspace values")
+	}
+	return nil
+}
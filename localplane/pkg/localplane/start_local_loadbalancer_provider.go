@@ -0,0 +1,33 @@
+package localplane
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	kindsvc "localplane/utils/kind"
+)
+
+// startLocalLoadBalancer starts the cloud-provider-kind load balancer of the
+// cluster of o via the provided kind client. It reports whether the load
+// balancer was started (false when disabled with SkipLoadBalancer). A
+// foreground load balancer runs until ctx is done.
+func startLocalLoadBalancer(ctx context.Context, kindClient *kindsvc.Client, o Options) (bool, error) {
+	if o.SkipLoadBalancer {
+		return false, nil
+	}
+	clusterName := o.Name
+	if !o.LoadBalancerForeground {
+		if err := kindClient.StartLoadBalancer(ctx, clusterName, true); err != nil {
+			return false, fmt.Errorf("starting load balancer in background: %w", err)
+		}
+		log.Info().Msg("load balancer started in background")
+		return true, nil
+	}
+	if err := kindClient.StartLoadBalancer(ctx, clusterName, false); err != nil {
+		return false, fmt.Errorf("running load balancer (foreground): %w", err)
+	}
+	log.Info().Msg("load balancer run completed")
+	return true, nil
+}
@@ -0,0 +1,56 @@
+package localplane
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	kindcfg "localplane/utils/kind/config"
+	"localplane/utils/remoterepo"
+
+	"github.com/rs/zerolog/log"
+)
+
+// syncRepoMirrors creates or updates the local mirrors of the remote
+// repositories that ask for one and mounts the mirrors directory into the
+// kind nodes. An unreachable remote with an existing mirror only warns, so
+// clusters can be created offline. It returns whether any mirror is used.
+func syncRepoMirrors(ctx context.Context, base string, repos []remoterepo.Repository, kindCfgPath string, kindCfg *kindcfg.KindCluster) (bool, error) {
+	mirrored := false
+	for _, repo := range repos {
+		if !repo.Mirror {
+			continue
+		}
+		mirrored = true
+		path := repo.MirrorPath(base)
+		stale, err := repo.SyncMirror(ctx, base)
+		switch {
+		case err != nil && stale:
+			log.Warn().Err(err).Str("name", repo.Name).Str("path", path).Msg("failed to update mirror; using the existing one")
+		case err != nil:
+			return true, fmt.Errorf("mirroring remote repository %s: %w", repo.Name, err)
+		default:
+			log.Info().Str("name", repo.Name).Str("path", path).Msg("mirror up to date")
+		}
+	}
+	if !mirrored {
+		return false, nil
+	}
+
+	if kindCfgPath == "" || kindCfg == nil {
+		log.Warn().Msg("no kind config available to mount the repository mirrors")
+		return true, nil
+	}
+	hostPath := filepath.Join(base, remoterepo.MirrorsDirName)
+	if err := os.MkdirAll(hostPath, 0o755); err != nil {
+		return true, fmt.Errorf("creating mirrors directory: %w", err)
+	}
+	kindcfg.AddExtraMount(kindCfg, hostPath, remoterepo.MirrorsMountPath)
+	if err := kindcfg.SaveKindConfig(kindCfgPath, kindCfg); err != nil {
+		log.Error().Err(err).Str("path", kindCfgPath).Msg("failed to write updated kind config with mirrors mount")
+	} else {
+		log.Info().Str("hostPath", hostPath).Str("containerPath", remoterepo.MirrorsMountPath).Msg("added mirrors mount to kind config")
+	}
+	return true, nil
+}
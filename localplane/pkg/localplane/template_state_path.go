@@ -0,0 +1,9 @@
+package localplane
+
+import "path/filepath"
+
+// TemplateStatePath returns where the workspace template installed into the
+// cluster's local-argo repo is recorded, `<base>/clusters/<clusterName>/template`.
+func TemplateStatePath(base, clusterName string) string {
+	return filepath.Join(base, "clusters", clusterName, "template")
+}
@@ -0,0 +1,25 @@
+package localplane
+
+import "localplane/config"
+
+// DefaultTimeouts are used for the zero fields of Options.Timeouts and
+// DestroyOptions.Timeouts: the timeouts section of config.Default.
+var DefaultTimeouts = config.Default().Timeouts
+
+// withDefaultTimeouts returns t with the DefaultTimeouts values of its zero
+// fields.
+func withDefaultTimeouts(t config.TimeoutsConfig) config.TimeoutsConfig {
+	if t.ClusterReady <= 0 {
+		t.ClusterReady = DefaultTimeouts.ClusterReady
+	}
+	if t.LoadBalancerService <= 0 {
+		t.LoadBalancerService = DefaultTimeouts.LoadBalancerService
+	}
+	if t.HelmInstall <= 0 {
+		t.HelmInstall = DefaultTimeouts.HelmInstall
+	}
+	if t.ClusterDeleted <= 0 {
+		t.ClusterDeleted = DefaultTimeouts.ClusterDeleted
+	}
+	return t
+}
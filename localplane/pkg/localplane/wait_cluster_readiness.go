@@ -0,0 +1,46 @@
+package localplane
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"localplane/utils/retry"
+
+	"github.com/rs/zerolog/log"
+)
+
+// waitForClusterReadiness polls kubectl, with the backoff of policy, to
+// determine whether the pods of the cluster of kubeconfigPath are healthy.
+// It fails when they aren't healthy within timeout or ctx is done.
+func waitForClusterReadiness(ctx context.Context, kubeconfigPath string, timeout time.Duration, policy retry.Policy) error {
+	err := retry.Until(ctx, policy, timeout, func(ctx context.Context) (bool, error) {
+		out, err := exec.CommandContext(ctx, "kubectl", "--kubeconfig", kubeconfigPath, "get", "pods", "--all-namespaces", "--no-headers").CombinedOutput()
+		outStr := strings.TrimSpace(string(out))
+		if err != nil {
+			log.Debug().Err(err).Str("output", outStr).Msg("kubectl get pods failed; cluster may not be ready yet")
+			return false, fmt.Errorf("kubectl get pods: %w", err)
+		}
+		if outStr == "" {
+			return false, fmt.Errorf("no pods yet")
+		}
+		for _, l := range strings.Split(outStr, "\n") {
+			f := strings.Fields(l)
+			if len(f) < 4 {
+				continue
+			}
+			status := f[3]
+			if status == "Pending" || strings.Contains(status, "CrashLoopBackOff") || status == "Error" || status == "Failed" {
+				return false, fmt.Errorf("pod %s/%s is %s", f[0], f[1], status)
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("cluster pods not healthy: %w", err)
+	}
+	log.Info().Str("kubeconfig", kubeconfigPath).Msg("cluster pods are healthy")
+	return nil
+}
@@ -0,0 +1,109 @@
+package argocd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// LocalRepoURL is the repoURL of the local-argo repo as mounted into the
+// repo-server (see the workspace chart bootstrap manifests).
+const LocalRepoURL = "file:///mnt/local-argo/.git"
+
+// Application is the part of an ArgoCD Application needed to tell whether
+// a change in a repository affects it.
+type Application struct {
+	Name    string
+	Sources []ApplicationSource
+}
+
+// ApplicationSource is one source (`spec.source` or an item of
+// `spec.sources`) of an Application.
+type ApplicationSource struct {
+	RepoURL        string
+	Path           string
+	TargetRevision string
+}
+
+// ListApplications returns the Applications in the ArgoCD namespace.
+func (c *Client) ListApplications(ctx context.Context) ([]Application, error) {
+	out, err := c.kubectl().GetJSON(ctx, "applications.argoproj.io", Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	type source struct {
+		RepoURL        string `json:"repoURL"`
+		Path           string `json:"path"`
+		TargetRevision string `json:"targetRevision"`
+	}
+	var raw struct {
+		Items []struct {
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+			Spec struct {
+				Source  *source  `json:"source"`
+				Sources []source `json:"sources"`
+			} `json:"spec"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(out, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse applications: %w", err)
+	}
+
+	apps := make([]Application, 0, len(raw.Items))
+	for _, it := range raw.Items {
+		app := Application{Name: it.Metadata.Name}
+		sources := it.Spec.Sources
+		if it.Spec.Source != nil {
+			sources = append([]source{*it.Spec.Source}, sources...)
+		}
+		for _, s := range sources {
+			app.Sources = append(app.Sources, ApplicationSource{RepoURL: s.RepoURL, Path: s.Path, TargetRevision: s.TargetRevision})
+		}
+		apps = append(apps, app)
+	}
+	return apps, nil
+}
+
+// RefreshApplication asks ArgoCD to compare the Application with its
+// repository again, instead of waiting for the next polling interval.
+func (c *Client) RefreshApplication(ctx context.Context, name string) error {
+	if err := c.kubectl().Annotate(ctx, "applications.argoproj.io", name, Namespace, "argocd.argoproj.io/refresh", "normal"); err != nil {
+		return fmt.Errorf("refreshing application %s: %w", name, err)
+	}
+	return nil
+}
+
+// AffectedApplications returns the names of the apps with a source in
+// repoURL whose path contains one of the changed files (paths relative to
+// the repository root), sorted.
+func AffectedApplications(apps []Application, repoURL string, changed []string) []string {
+	var names []string
+	for _, app := range apps {
+		if sourcesContain(app.Sources, repoURL, changed) {
+			names = append(names, app.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sourcesContain(sources []ApplicationSource, repoURL string, changed []string) bool {
+	for _, s := range sources {
+		if strings.TrimSuffix(s.RepoURL, "/") != strings.TrimSuffix(repoURL, "/") {
+			continue
+		}
+		dir := strings.Trim(path.Clean("/"+s.Path), "/")
+		for _, f := range changed {
+			if dir == "" || f == dir || strings.HasPrefix(f, dir+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -1,18 +1,13 @@
 package argocd
 
 import (
-	"errors"
+	"context"
 	"fmt"
-	"localplane/config"
-	stdlog "log"
-	"os"
+	"localplane/utils/helm"
+	"localplane/utils/ingress"
+	"localplane/utils/kubectl"
+	"localplane/utils/retry"
 	"time"
-
-	"github.com/rs/zerolog/log"
-	"helm.sh/helm/v3/pkg/action"
-	"helm.sh/helm/v3/pkg/chart/loader"
-	"helm.sh/helm/v3/pkg/cli"
-	"helm.sh/helm/v3/pkg/storage/driver"
 )
 
 // RepoMount defines a name/hostPath/mountPath triple for mounting a repository
@@ -27,6 +22,9 @@ type RepoMount struct {
 // configuration such as a kubeconfig path.
 type Client struct {
 	Kubeconfig string
+	// Retry is the backoff of the kubectl calls failing with a transient
+	// error.
+	Retry retry.Policy
 }
 
 // NewClient creates a configured Client. Pass empty string for defaults.
@@ -34,14 +32,66 @@ func NewClient(kubeconfig string) *Client {
 	return &Client{Kubeconfig: kubeconfig}
 }
 
+// kubectl returns the kubectl client of the cluster of c.
+func (c *Client) kubectl() *kubectl.Client {
+	kubeconfig := c.Kubeconfig
+	client := kubectl.NewClient(&kubeconfig, nil)
+	client.Retry = c.Retry
+	return client
+}
+
+const (
+	// ChartRepoURL is the official Argo Helm repository.
+	ChartRepoURL = "https://argoproj.github.io/argo-helm"
+	// ChartName is the name of the ArgoCD chart in ChartRepoURL.
+	ChartName = "argo-cd"
+	// Namespace is where ArgoCD is installed.
+	Namespace = "argocd"
+	// DefaultChartVersion is the argo-cd chart version installed unless
+	// overridden, pinned so clusters are reproducible.
+	DefaultChartVersion = "8.5.0"
+)
+
+// InstallOptions tweaks the chart and values used to install ArgoCD.
+type InstallOptions struct {
+	// ChartVersion of the argo-cd chart in ChartRepoURL. Empty means
+	// DefaultChartVersion. Ignored when ChartPath is set.
+	ChartVersion string
+	// ChartPath loads the chart from a local .tgz archive or unpacked chart
+	// directory instead of the repository, e.g. for offline installs.
+	ChartPath string
+	// ValuesFiles are user values files merged on top of the built-in values,
+	// later files taking precedence (like `helm -f a.yaml -f b.yaml`).
+	ValuesFiles []string
+
+	// TLS enables TLS on the ArgoCD server ingress. The certificate is issued
+	// by the cert-manager ClusterIssuer named ClusterIssuer.
+	TLS           bool
+	ClusterIssuer string
+	// Ingress is the controller exposing the ArgoCD server. The zero value
+	// falls back to ingress.DefaultType.
+	Ingress ingress.Controller
+	// Secure disables anonymous admin access; users log in as `admin` with
+	// AdminPassword (required when Secure is set).
+	Secure        bool
+	AdminPassword string
+	// Domain is the DNS domain of the server hostname, argocd.<Domain>
+	// ("localplane" when empty).
+	Domain string
+	// Timeout bounds the wait for the release to be ready (helm.Release
+	// default when zero).
+	Timeout time.Duration
+}
+
 // InstallOrUpgradeArgoCD installs or upgrades ArgoCD using the Helm SDK (upgrade --install).
 // - mounts: list of RepoMount to add to repoServer.volumes and repoServer.volumeMounts
-func (c *Client) InstallOrUpgradeArgoCD(mounts []RepoMount) (string, error) {
-	// use official argo-cd chart from Argo Helm
+// - opts: optional tweaks such as TLS on the server ingress
+func (c *Client) InstallOrUpgradeArgoCD(ctx context.Context, mounts []RepoMount, opts InstallOptions) (string, error) {
 	release := "argocd"
-	namespace := "argocd"
-	repoURL := "https://argoproj.github.io/argo-helm"
-	chart := "argo-cd"
+	namespace := Namespace
+	if opts.Secure && opts.AdminPassword == "" {
+		return "", fmt.Errorf("secure mode requires an admin password")
+	}
 	// prepare values map
 	values := map[string]interface{}{}
 	repoServer := map[string]interface{}{}
@@ -69,25 +119,77 @@ func (c *Client) InstallOrUpgradeArgoCD(mounts []RepoMount) (string, error) {
 	repoServer["volumes"] = vols
 	repoServer["volumeMounts"] = vms
 
-	// add an ingress with the local dnsmasq domain (argocd.k8s-bench.local)
-	host := "argocd.localplane"
+	// add an ingress with the local dnsmasq domain (argocd.localplane)
+	domain := opts.Domain
+	if domain == "" {
+		domain = "localplane"
+	}
+	host := "argocd." + domain
 	global["domain"] = host
 	configs["params"] = map[string]interface{}{
 		"server.insecure": "true",
 	}
 
-	// configure access without login
-	configs["cm"] = map[string]interface{}{
-		"users.anonymous.enabled": "true",
-	}
-	configs["rbac"] = map[string]interface{}{
-		"policy.default": "role:admin",
+	if opts.Secure {
+		// secure mode: anonymous access stays off and the admin account logs
+		// in with the provided password
+		hash, err := HashPassword(opts.AdminPassword)
+		if err != nil {
+			return "", err
+		}
+		configs["cm"] = map[string]interface{}{
+			"users.anonymous.enabled": "false",
+			"admin.enabled":           "true",
+		}
+		configs["rbac"] = map[string]interface{}{
+			"policy.default": "",
+		}
+		configs["secret"] = map[string]interface{}{
+			"argocdServerAdminPassword":      hash,
+			"argocdServerAdminPasswordMtime": time.Now().UTC().Format(time.RFC3339),
+		}
+	} else {
+		// configure access without login
+		configs["cm"] = map[string]interface{}{
+			"users.anonymous.enabled": "true",
+		}
+		configs["rbac"] = map[string]interface{}{
+			"policy.default": "role:admin",
+		}
 	}
 
-	server["ingress"] = map[string]interface{}{
-		"enabled":          true,
-		"ingressClassName": "haproxy",
-		"tls":              false,
+	controller := opts.Ingress
+	if controller.Type == "" {
+		controller, _ = ingress.Lookup(ingress.DefaultType)
+	}
+	if controller.UsesGatewayAPI() {
+		// Gateway API controllers don't serve Ingress objects; attach an
+		// HTTPRoute to the shared Gateway instead.
+		server["httproute"] = map[string]interface{}{
+			"enabled":   true,
+			"hostnames": []interface{}{host},
+			"parentRefs": []interface{}{map[string]interface{}{
+				"name":      controller.Gateway,
+				"namespace": controller.Namespace,
+			}},
+		}
+	} else {
+		serverIngress := map[string]interface{}{
+			"enabled":          true,
+			"ingressClassName": controller.ClassName,
+			"tls":              false,
+		}
+		// TLS is terminated at the ingress controller, so the server itself keeps
+		// running in insecure mode behind it.
+		if opts.TLS {
+			serverIngress["tls"] = true
+			if opts.ClusterIssuer != "" {
+				serverIngress["annotations"] = map[string]interface{}{
+					"cert-manager.io/cluster-issuer": opts.ClusterIssuer,
+				}
+			}
+		}
+		server["ingress"] = serverIngress
 	}
 
 	values["global"] = global
@@ -95,68 +197,23 @@ func (c *Client) InstallOrUpgradeArgoCD(mounts []RepoMount) (string, error) {
 	values["repoServer"] = repoServer
 	values["server"] = server
 
-	// helm SDK config
-	settings := cli.New()
-	if c != nil && c.Kubeconfig != "" {
-		settings.KubeConfig = c.Kubeconfig
-	}
-	var cfg action.Configuration
-	var helmOutput = func(format string, v ...interface{}) { /* no-op */ }
-	if config.CliConfig.Debug {
-		helmOutput = stdlog.Printf
-	}
-	if err := cfg.Init(settings.RESTClientGetter(), namespace, os.Getenv("HELM_DRIVER"), helmOutput); err != nil {
-		return "", fmt.Errorf("failed to init helm configuration: %w", err)
-	}
-
-	// locate and load chart (supports repo URL via ChartPathOptions)
-	cp := action.ChartPathOptions{RepoURL: repoURL}
-	chartPath, err := cp.LocateChart(chart, settings)
-	if err != nil {
-		return "", fmt.Errorf("locate chart: %w", err)
+	version := opts.ChartVersion
+	if version == "" {
+		version = DefaultChartVersion
 	}
-	ch, err := loader.Load(chartPath)
-	if err != nil {
-		return "", fmt.Errorf("load chart: %w", err)
+	kubeconfig := ""
+	if c != nil {
+		kubeconfig = c.Kubeconfig
 	}
-
-	// Prepare variables to capture release name and version after install/upgrade.
-	var relName string
-	var relVersion int
-	// If not found -> install, else upgrade.
-	g := action.NewGet(&cfg)
-	_, err = g.Run(release)
-	if err != nil {
-		// If the release is not found, perform an install.
-		if errors.Is(err, driver.ErrReleaseNotFound) {
-			i := action.NewInstall(&cfg)
-			i.ReleaseName = release
-			i.Namespace = namespace
-			i.CreateNamespace = true
-			i.Timeout = time.Duration(5) * time.Minute
-			i.Wait = true
-			rel, err := i.Run(ch, values)
-			if err != nil {
-				return "", fmt.Errorf("install failed: %w", err)
-			}
-			relName = rel.Name
-			relVersion = rel.Version
-		} else {
-			return "", fmt.Errorf("failed checking release: %w", err)
-		}
-	} else {
-		// Release exists -> perform upgrade.
-		u := action.NewUpgrade(&cfg)
-		u.Namespace = namespace
-		u.Wait = true
-		rel, err := u.Run(release, ch, values)
-		if err != nil {
-			return "", fmt.Errorf("upgrade failed: %w", err)
-		}
-		relName = rel.Name
-		relVersion = rel.Version
-	}
-
-	log.Info().Str("release", relName).Int("version", relVersion).Str("namespace", namespace).Msg("argocd installed/updated via helm sdk")
-	return fmt.Sprintf("release %s (version %d)", relName, relVersion), nil
+	return helm.NewClient(kubeconfig).InstallOrUpgrade(ctx, helm.Release{
+		Name:        release,
+		Namespace:   namespace,
+		Chart:       ChartName,
+		RepoURL:     ChartRepoURL,
+		Version:     version,
+		ChartPath:   opts.ChartPath,
+		Values:      values,
+		ValuesFiles: opts.ValuesFiles,
+		Timeout:     opts.Timeout,
+	})
 }
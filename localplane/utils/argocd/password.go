@@ -0,0 +1,99 @@
+package argocd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// AdminPasswordFileName is the file, inside a cluster directory, holding the
+// ArgoCD admin password of clusters created in secure mode.
+const AdminPasswordFileName = "argocd-admin-password"
+
+const passwordAlphabet = "abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// GeneratePassword returns a random password of the given length drawn from
+// an alphabet without look-alike characters.
+func GeneratePassword(length int) (string, error) {
+	var b strings.Builder
+	max := big.NewInt(int64(len(passwordAlphabet)))
+	for i := 0; i < length; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("generating password: %w", err)
+		}
+		b.WriteByte(passwordAlphabet[n.Int64()])
+	}
+	return b.String(), nil
+}
+
+// HashPassword returns the bcrypt hash ArgoCD expects in argocd-secret.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("hashing admin password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// AdminPasswordPath returns where the admin password of the cluster stored in
+// clusterDir is kept.
+func AdminPasswordPath(clusterDir string) string {
+	return filepath.Join(clusterDir, AdminPasswordFileName)
+}
+
+// LoadAdminPassword reads the stored admin password. It returns an empty
+// string and no error when the cluster was not created in secure mode.
+func LoadAdminPassword(clusterDir string) (string, error) {
+	data, err := os.ReadFile(AdminPasswordPath(clusterDir))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("reading admin password: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// SaveAdminPassword stores the admin password readable by the current user only.
+func SaveAdminPassword(clusterDir, password string) error {
+	if err := os.MkdirAll(clusterDir, 0o755); err != nil {
+		return fmt.Errorf("creating cluster directory %s: %w", clusterDir, err)
+	}
+	if err := os.WriteFile(AdminPasswordPath(clusterDir), []byte(password+"\n"), 0o600); err != nil {
+		return fmt.Errorf("writing admin password: %w", err)
+	}
+	return nil
+}
+
+// SetAdminPassword updates the admin password of a running ArgoCD by patching
+// argocd-secret. The server picks the change up without a restart.
+func (c *Client) SetAdminPassword(ctx context.Context, password string) error {
+	hash, err := HashPassword(password)
+	if err != nil {
+		return err
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"stringData": map[string]string{
+			"admin.password":      hash,
+			"admin.passwordMtime": time.Now().UTC().Format(time.RFC3339),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("encoding argocd-secret patch: %w", err)
+	}
+
+	if err := c.kubectl().Patch(ctx, "secret", "argocd-secret", Namespace, string(patch)); err != nil {
+		return fmt.Errorf("updating argocd admin password: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package argocd
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+
+	"localplane/utils/remoterepo"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// RepositoryManifests renders, for each remote repository, the repository
+// Secret ArgoCD reads its URL and credentials from (like the local-argo
+// `argo-bootstrap-repo.yaml`) and, when the repository sets a path, an
+// Application deploying that path from its branch. Mirrored repositories are
+// registered with their mounted mirror URL and no credentials.
+func RepositoryManifests(repos []remoterepo.Repository) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, repo := range repos {
+		stringData := map[string]string{
+			"type": "git",
+			"name": repo.Name,
+			"url":  repo.ClusterURL(),
+		}
+		if !repo.Mirror {
+			if repo.Auth.Username != "" {
+				stringData["username"] = repo.Auth.Username
+			}
+			if repo.Auth.Password != "" {
+				stringData["password"] = repo.Auth.Password
+			}
+			if repo.Auth.SSHPrivateKey != "" {
+				stringData["sshPrivateKey"] = repo.Auth.SSHPrivateKey
+			}
+		}
+		docs := []map[string]interface{}{{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata": map[string]interface{}{
+				"name":      "repo-" + repo.Name,
+				"namespace": Namespace,
+				"labels": map[string]string{
+					"argocd.argoproj.io/secret-type": "repository",
+					"app.kubernetes.io/managed-by":   "localplane",
+				},
+			},
+			"type":       "Opaque",
+			"stringData": stringData,
+		}}
+		if repo.Path != "" {
+			docs = append(docs, map[string]interface{}{
+				"apiVersion": "argoproj.io/v1alpha1",
+				"kind":       "Application",
+				"metadata": map[string]interface{}{
+					"name":       repo.Name,
+					"namespace":  Namespace,
+					"finalizers": []string{"resources-finalizer.argocd.argoproj.io"},
+				},
+				"spec": map[string]interface{}{
+					"project": "default",
+					"source": map[string]interface{}{
+						"repoURL":        repo.ClusterURL(),
+						"targetRevision": repo.Branch,
+						"path":           path.Clean(repo.Path),
+					},
+					"destination": map[string]interface{}{
+						"server":    "https://kubernetes.default.svc",
+						"namespace": repo.Namespace,
+					},
+					"syncPolicy": map[string]interface{}{
+						"automated":   map[string]bool{"prune": true, "selfHeal": true},
+						"syncOptions": []string{"CreateNamespace=true"},
+					},
+				},
+			})
+		}
+		for _, doc := range docs {
+			out, err := yaml.Marshal(doc)
+			if err != nil {
+				return nil, fmt.Errorf("rendering manifests of repository %s: %w", repo.Name, err)
+			}
+			buf.WriteString("---\n")
+			buf.Write(out)
+		}
+	}
+	return buf.Bytes(), nil
+}
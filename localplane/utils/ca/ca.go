@@ -0,0 +1,213 @@
+package ca
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// CertFileName is the file name of the CA certificate inside Client.Dir.
+	CertFileName = "ca.crt"
+	// KeyFileName is the file name of the CA private key inside Client.Dir.
+	KeyFileName = "ca.key"
+	// SecretName is the name of the TLS Secret holding the CA inside the cluster.
+	SecretName = "localplane-ca"
+	// SecretNamespace is the namespace cert-manager reads ClusterIssuer secrets from.
+	SecretNamespace = "cert-manager"
+	// ClusterIssuerName is the name of the cert-manager ClusterIssuer backed by the CA.
+	ClusterIssuerName = "localplane-ca"
+)
+
+// Client manages the workspace certificate authority stored under Dir.
+type Client struct {
+	Dir string
+}
+
+// NewClient creates a CA Client rooted at dir (usually <directory>/ca).
+func NewClient(dir string) *Client {
+	return &Client{Dir: dir}
+}
+
+// CertPath returns the path of the CA certificate.
+func (c *Client) CertPath() string {
+	return filepath.Join(c.Dir, CertFileName)
+}
+
+// KeyPath returns the path of the CA private key.
+func (c *Client) KeyPath() string {
+	return filepath.Join(c.Dir, KeyFileName)
+}
+
+// EnsureCA creates a self-signed CA under Dir unless one already exists.
+// Existing files are reused so browsers and trust stores keep trusting
+// certificates issued by previous clusters of the same workspace.
+func (c *Client) EnsureCA() error {
+	if _, err := os.Stat(c.CertPath()); err == nil {
+		if _, err := os.Stat(c.KeyPath()); err == nil {
+			log.Debug().Str("path", c.CertPath()).Msg("workspace CA already exists")
+			return nil
+		}
+	}
+
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return fmt.Errorf("creating CA directory %s: %w", c.Dir, err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return fmt.Errorf("generating CA key: %w", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return fmt.Errorf("generating CA serial: %w", err)
+	}
+
+	hostname, _ := os.Hostname()
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{"localplane"},
+			CommonName:   fmt.Sprintf("localplane workspace CA (%s)", hostname),
+		},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return fmt.Errorf("creating CA certificate: %w", err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("encoding CA key: %w", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+
+	if err := os.WriteFile(c.KeyPath(), keyPEM, 0o600); err != nil {
+		return fmt.Errorf("writing CA key: %w", err)
+	}
+	if err := os.WriteFile(c.CertPath(), certPEM, 0o644); err != nil {
+		return fmt.Errorf("writing CA certificate: %w", err)
+	}
+
+	log.Info().Str("path", c.CertPath()).Msg("created workspace CA")
+	return nil
+}
+
+// SecretManifest renders a kubernetes.io/tls Secret (and its namespace)
+// holding the CA so that the cert-manager ClusterIssuer can sign with it.
+func (c *Client) SecretManifest() ([]byte, error) {
+	cert, err := os.ReadFile(c.CertPath())
+	if err != nil {
+		return nil, fmt.Errorf("reading CA certificate: %w", err)
+	}
+	key, err := os.ReadFile(c.KeyPath())
+	if err != nil {
+		return nil, fmt.Errorf("reading CA key: %w", err)
+	}
+
+	manifest := fmt.Sprintf(`apiVersion: v1
+kind: Namespace
+metadata:
+  name: %s
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: %s
+  namespace: %s
+type: kubernetes.io/tls
+data:
+  tls.crt: %s
+  tls.key: %s
+`, SecretNamespace, SecretName, SecretNamespace, base64.StdEncoding.EncodeToString(cert), base64.StdEncoding.EncodeToString(key))
+	return []byte(manifest), nil
+}
+
+// trustStore describes where a Linux distribution expects extra anchors and
+// which command refreshes the bundle.
+type trustStore struct {
+	dir    string
+	update []string
+}
+
+var trustStores = []trustStore{
+	// Debian, Ubuntu, Alpine
+	{dir: "/usr/local/share/ca-certificates", update: []string{"update-ca-certificates"}},
+	// Fedora, RHEL, CentOS
+	{dir: "/etc/pki/ca-trust/source/anchors", update: []string{"update-ca-trust", "extract"}},
+	// Arch Linux
+	{dir: "/etc/ca-certificates/trust-source/anchors", update: []string{"trust", "extract-compat"}},
+}
+
+// TrustSystem installs the CA certificate into the Linux system trust store.
+// sudo is used when the current user is not root.
+func (c *Client) TrustSystem(ctx context.Context) error {
+	if _, err := os.Stat(c.CertPath()); err != nil {
+		return fmt.Errorf("CA certificate not found at %s (create a cluster with --tls first): %w", c.CertPath(), err)
+	}
+
+	var store *trustStore
+	for i := range trustStores {
+		if _, err := os.Stat(trustStores[i].dir); err != nil {
+			continue
+		}
+		if _, err := exec.LookPath(trustStores[i].update[0]); err != nil {
+			continue
+		}
+		store = &trustStores[i]
+		break
+	}
+	if store == nil {
+		return fmt.Errorf("no supported system trust store found; import %s manually", c.CertPath())
+	}
+
+	dest := filepath.Join(store.dir, "localplane-ca.crt")
+	if err := runPrivileged(ctx, "install", "-m", "0644", c.CertPath(), dest); err != nil {
+		return fmt.Errorf("copying CA into %s: %w", store.dir, err)
+	}
+	if err := runPrivileged(ctx, store.update...); err != nil {
+		return fmt.Errorf("refreshing system trust store: %w", err)
+	}
+
+	log.Info().Str("path", dest).Msg("installed workspace CA into system trust store")
+	return nil
+}
+
+// runPrivileged runs the command directly when root, through sudo otherwise.
+// stdin is attached so the user can type their sudo password.
+func runPrivileged(ctx context.Context, args ...string) error {
+	if os.Geteuid() != 0 {
+		if _, err := exec.LookPath("sudo"); err != nil {
+			return fmt.Errorf("sudo required but not installed")
+		}
+		args = append([]string{"sudo"}, args...)
+	}
+	log.Debug().Strs("cmd", args).Msg("running command")
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,85 @@
+package clierror
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Exit codes of the CLI, one per failure class, so scripts can tell why a
+// command failed. Any error without a class exits with ExitGeneric.
+const (
+	ExitOK      = 0
+	ExitGeneric = 1
+	// ExitUsage: invalid flags, arguments or configuration.
+	ExitUsage = 2
+	// ExitAborted: the user declined a confirmation.
+	ExitAborted = 3
+	// ExitWorkspace: the local-argo repo, workspace template or mirrors
+	// couldn't be set up.
+	ExitWorkspace = 4
+	// ExitCluster: kind failed to create or delete the cluster.
+	ExitCluster = 5
+	// ExitGitOps: the GitOps engine or the bootstrap manifests failed.
+	ExitGitOps = 6
+	// ExitTLS: the workspace CA couldn't be set up.
+	ExitTLS = 7
+	// ExitNotFound: the cluster doesn't exist.
+	ExitNotFound = 8
+)
+
+var classes = map[int]string{
+	ExitGeneric:   "error",
+	ExitUsage:     "usage",
+	ExitAborted:   "aborted",
+	ExitWorkspace: "workspace",
+	ExitCluster:   "cluster",
+	ExitGitOps:    "gitops",
+	ExitTLS:       "tls",
+	ExitNotFound:  "not-found",
+}
+
+// Error is an error with the exit code of its failure class.
+type Error struct {
+	Code int
+	Err  error
+}
+
+func (e *Error) Error() string { return e.Err.Error() }
+func (e *Error) Unwrap() error { return e.Err }
+
+// New classifies err with code. It returns nil when err is nil.
+func New(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// Newf formats a new error classified with code.
+func Newf(code int, format string, args ...interface{}) error {
+	return &Error{Code: code, Err: fmt.Errorf(format, args...)}
+}
+
+// Code returns the exit code for err: ExitOK when nil, the code of the
+// outermost classified error, ExitGeneric otherwise.
+func Code(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ExitGeneric
+}
+
+// Class returns the name of the failure class of err ("" when nil).
+func Class(err error) string {
+	if err == nil {
+		return ""
+	}
+	if class, ok := classes[Code(err)]; ok {
+		return class
+	}
+	return classes[ExitGeneric]
+}
@@ -0,0 +1,75 @@
+package flux
+
+import (
+	"context"
+	"time"
+
+	"localplane/utils/helm"
+)
+
+const (
+	// Namespace is where the Flux controllers are installed.
+	Namespace = "flux-system"
+	// ChartRepoURL is the Flux community Helm repository.
+	ChartRepoURL = "https://fluxcd-community.github.io/helm-charts"
+	// ChartName is the name of the Flux chart in ChartRepoURL.
+	ChartName = "flux2"
+	// DefaultChartVersion is the flux2 chart version installed unless
+	// overridden, pinned so clusters are reproducible.
+	DefaultChartVersion = "2.16.0"
+)
+
+// Client is a small helper to configure operations that may need common
+// configuration such as a kubeconfig path.
+type Client struct {
+	Kubeconfig string
+}
+
+// NewClient creates a configured Client. Pass empty string for defaults.
+func NewClient(kubeconfig string) *Client {
+	return &Client{Kubeconfig: kubeconfig}
+}
+
+// InstallOptions tweaks the chart used to install Flux.
+type InstallOptions struct {
+	// ChartVersion of the flux2 chart. Empty means DefaultChartVersion.
+	ChartVersion string
+	// ChartPath loads the chart from a local .tgz archive or directory.
+	ChartPath string
+	// ValuesFiles are merged on top of the built-in values.
+	ValuesFiles []string
+	// Timeout bounds the wait for the release to be ready (helm.Release
+	// default when zero).
+	Timeout time.Duration
+}
+
+// InstallOrUpgradeFlux installs or upgrades the Flux controllers using the
+// Helm SDK. Only the controllers needed to reconcile the local workspace
+// (source, helm and kustomize) are enabled.
+func (c *Client) InstallOrUpgradeFlux(ctx context.Context, opts InstallOptions) (string, error) {
+	values := map[string]interface{}{
+		"imageAutomationController": map[string]interface{}{"create": false},
+		"imageReflectionController": map[string]interface{}{"create": false},
+		"notificationController":    map[string]interface{}{"create": false},
+	}
+
+	version := opts.ChartVersion
+	if version == "" {
+		version = DefaultChartVersion
+	}
+	kubeconfig := ""
+	if c != nil {
+		kubeconfig = c.Kubeconfig
+	}
+	return helm.NewClient(kubeconfig).InstallOrUpgrade(ctx, helm.Release{
+		Name:        "flux",
+		Namespace:   Namespace,
+		Chart:       ChartName,
+		RepoURL:     ChartRepoURL,
+		Version:     version,
+		ChartPath:   opts.ChartPath,
+		Values:      values,
+		ValuesFiles: opts.ValuesFiles,
+		Timeout:     opts.Timeout,
+	})
+}
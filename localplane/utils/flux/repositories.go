@@ -0,0 +1,87 @@
+package flux
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+
+	"localplane/utils/remoterepo"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// RepositoryManifests renders, for each remote repository, a GitRepository
+// (with a credentials Secret when needed) and, when the repository sets a
+// path, a Kustomization applying that path into its namespace. Flux runs in
+// the cluster and can't read host mirrors, and ssh would also need
+// known_hosts, so both are rejected.
+func RepositoryManifests(repos []remoterepo.Repository) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, repo := range repos {
+		if repo.Mirror {
+			return nil, fmt.Errorf("repository %s: mirrors are not supported with flux", repo.Name)
+		}
+		if repo.Auth.SSHPrivateKey != "" {
+			return nil, fmt.Errorf("repository %s: ssh credentials are not supported with flux; use an https token", repo.Name)
+		}
+
+		spec := map[string]interface{}{
+			"interval": "1m",
+			"url":      repo.URL,
+			"ref":      map[string]string{"branch": repo.Branch},
+		}
+		var docs []map[string]interface{}
+		if repo.HasCredentials() {
+			username := repo.Auth.Username
+			if username == "" {
+				username = "git"
+			}
+			docs = append(docs, map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Secret",
+				"metadata": map[string]interface{}{
+					"name":      "repo-" + repo.Name,
+					"namespace": Namespace,
+					"labels":    map[string]string{"app.kubernetes.io/managed-by": "localplane"},
+				},
+				"type":       "Opaque",
+				"stringData": map[string]string{"username": username, "password": repo.Auth.Password},
+			})
+			spec["secretRef"] = map[string]string{"name": "repo-" + repo.Name}
+		}
+		docs = append(docs, map[string]interface{}{
+			"apiVersion": "source.toolkit.fluxcd.io/v1",
+			"kind":       "GitRepository",
+			"metadata":   map[string]interface{}{"name": repo.Name, "namespace": Namespace},
+			"spec":       spec,
+		})
+		if repo.Path != "" {
+			// unlike ArgoCD, Flux doesn't create the target namespace
+			docs = append(docs, map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Namespace",
+				"metadata":   map[string]interface{}{"name": repo.Namespace},
+			}, map[string]interface{}{
+				"apiVersion": "kustomize.toolkit.fluxcd.io/v1",
+				"kind":       "Kustomization",
+				"metadata":   map[string]interface{}{"name": repo.Name, "namespace": Namespace},
+				"spec": map[string]interface{}{
+					"interval":        "1m",
+					"path":            "./" + path.Clean(repo.Path),
+					"prune":           true,
+					"targetNamespace": repo.Namespace,
+					"sourceRef":       map[string]string{"kind": "GitRepository", "name": repo.Name},
+				},
+			})
+		}
+		for _, doc := range docs {
+			out, err := yaml.Marshal(doc)
+			if err != nil {
+				return nil, fmt.Errorf("rendering manifests of repository %s: %w", repo.Name, err)
+			}
+			buf.WriteString("---\n")
+			buf.Write(out)
+		}
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,146 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// CurrentBranch returns the branch checked out in the client's Path. It
+// works in a repository without commits too.
+func (c *Client) CurrentBranch() (string, error) {
+	repo, err := c.open()
+	if err != nil {
+		return "", err
+	}
+	head, err := repo.Storer.Reference(plumbing.HEAD)
+	if err != nil {
+		return "", fmt.Errorf("reading HEAD: %w", err)
+	}
+	if head.Type() != plumbing.SymbolicReference || !head.Target().IsBranch() {
+		return "", fmt.Errorf("HEAD is detached at %s", head.Hash())
+	}
+	return head.Target().Short(), nil
+}
+
+// HasCommit reports whether rev (a branch, tag or commit) resolves to a
+// commit. A freshly initialized branch has none.
+func (c *Client) HasCommit(rev string) bool {
+	repo, err := c.open()
+	if err != nil {
+		return false
+	}
+	_, err = repo.ResolveRevision(plumbing.Revision(rev))
+	return err == nil
+}
+
+// Branches returns the local branch names, sorted.
+func (c *Client) Branches() ([]string, error) {
+	repo, err := c.open()
+	if err != nil {
+		return nil, err
+	}
+	iter, err := repo.Branches()
+	if err != nil {
+		return nil, fmt.Errorf("listing branches: %w", err)
+	}
+	var names []string
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		names = append(names, ref.Name().Short())
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing branches: %w", err)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// CreateBranch creates branch at the current HEAD commit without checking it
+// out. It fails when the branch already exists or HEAD has no commit.
+func (c *Client) CreateBranch(branch string) error {
+	repo, err := c.open()
+	if err != nil {
+		return err
+	}
+	name := plumbing.NewBranchReferenceName(branch)
+	if err := name.Validate(); err != nil {
+		return fmt.Errorf("invalid branch name %q: %w", branch, err)
+	}
+	if _, err := repo.Reference(name, false); err == nil {
+		return fmt.Errorf("branch %s already exists", branch)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return fmt.Errorf("resolving HEAD (no commit yet?): %w", err)
+	}
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(name, head.Hash())); err != nil {
+		return fmt.Errorf("creating branch %s: %w", branch, err)
+	}
+	return nil
+}
+
+// Checkout switches the working tree to branch. When the branch doesn't
+// exist it is created from the current HEAD, keeping local changes like
+// `git checkout -b`. Like git, switching to an existing branch is refused
+// while tracked files have uncommitted changes.
+func (c *Client) Checkout(branch string) error {
+	repo, wt, err := c.worktree()
+	if err != nil {
+		return err
+	}
+	name := plumbing.NewBranchReferenceName(branch)
+	if err := name.Validate(); err != nil {
+		return fmt.Errorf("invalid branch name %q: %w", branch, err)
+	}
+
+	if _, err := repo.Reference(name, false); err != nil {
+		// new branch at the current commit: the working tree doesn't change,
+		// only HEAD moves (an unborn HEAD just gets renamed)
+		head, err := repo.Head()
+		if err == nil {
+			if err := repo.Storer.SetReference(plumbing.NewHashReference(name, head.Hash())); err != nil {
+				return fmt.Errorf("creating branch %s: %w", branch, err)
+			}
+		} else if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return fmt.Errorf("resolving HEAD: %w", err)
+		}
+		if err := repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, name)); err != nil {
+			return fmt.Errorf("git checkout %s failed: %w", branch, err)
+		}
+		return nil
+	}
+
+	if err := wt.Checkout(&gogit.CheckoutOptions{Branch: name}); err != nil {
+		return fmt.Errorf("git checkout %s failed: %w", branch, err)
+	}
+	return nil
+}
+
+// Reset moves the current branch to rev (a branch, tag or commit; HEAD when
+// empty). With hard, the index and the working tree are reset too and local
+// changes to tracked files are lost; otherwise only the index is reset.
+func (c *Client) Reset(rev string, hard bool) error {
+	repo, wt, err := c.worktree()
+	if err != nil {
+		return err
+	}
+	if rev == "" {
+		rev = "HEAD"
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return fmt.Errorf("resolving %s: %w", rev, err)
+	}
+	mode := gogit.MixedReset
+	if hard {
+		mode = gogit.HardReset
+	}
+	if err := wt.Reset(&gogit.ResetOptions{Commit: *hash, Mode: mode}); err != nil {
+		return fmt.Errorf("git reset %s failed: %w", rev, err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+var hashRegexp = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// Clone clones url into path and checks out ref: a branch or a tag (cloned
+// shallow), a full commit hash, or the remote HEAD when empty. path must not
+// exist yet: it is removed when the clone fails.
+func Clone(ctx context.Context, path, url, ref string, auth Auth) error {
+	method, err := auth.method()
+	if err != nil {
+		return err
+	}
+
+	if hashRegexp.MatchString(ref) {
+		repo, err := gogit.PlainCloneContext(ctx, path, false, &gogit.CloneOptions{URL: url, Auth: method, NoCheckout: true})
+		if err != nil {
+			return fmt.Errorf("cloning %s: %w", url, err)
+		}
+		wt, err := repo.Worktree()
+		if err != nil {
+			return fmt.Errorf("opening worktree of %s: %w", path, err)
+		}
+		if err := wt.Checkout(&gogit.CheckoutOptions{Hash: plumbing.NewHash(ref), Force: true}); err != nil {
+			return fmt.Errorf("checking out %s: %w", ref, err)
+		}
+		return nil
+	}
+
+	names := []plumbing.ReferenceName{""}
+	if ref != "" {
+		names = []plumbing.ReferenceName{plumbing.NewBranchReferenceName(ref), plumbing.NewTagReferenceName(ref)}
+	}
+	for _, name := range names {
+		_, err = gogit.PlainCloneContext(ctx, path, false, &gogit.CloneOptions{
+			URL:           url,
+			Auth:          method,
+			ReferenceName: name,
+			SingleBranch:  true,
+			Depth:         1,
+		})
+		if err == nil {
+			return nil
+		}
+		// a failed clone leaves a partial repository behind
+		os.RemoveAll(path)
+	}
+	if ref != "" {
+		return fmt.Errorf("cloning %s at %s: %w", url, ref, err)
+	}
+	return fmt.Errorf("cloning %s: %w", url, err)
+}
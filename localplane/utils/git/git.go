@@ -1,13 +1,34 @@
 package git
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
+	"path/filepath"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// DefaultBranch is the initial branch of repositories created by
+// InitializeGitRepo, and the branch the GitOps engines track by default.
+const DefaultBranch = "main"
+
+// AuthorName and AuthorEmail identify the commits made by the CLI, so
+// committing works without a `user.name` / `user.email` git config.
+const (
+	AuthorName  = "localplane"
+	AuthorEmail = "localplane@localhost"
 )
 
+// ErrNothingToCommit is returned by CommitAll when the working tree is clean.
+var ErrNothingToCommit = errors.New("nothing to commit, working tree clean")
+
 // Client provides a simple API around common git operations rooted at Path.
+// It uses go-git, so no git binary is required.
 type Client struct {
 	Path string
 }
@@ -17,8 +38,36 @@ func NewClient(path string) *Client {
 	return &Client{Path: path}
 }
 
+// open opens the repository rooted at the client's Path.
+func (c *Client) open() (*gogit.Repository, error) {
+	repo, err := gogit.PlainOpen(c.Path)
+	if err != nil {
+		return nil, fmt.Errorf("opening git repository %s: %w", c.Path, err)
+	}
+	return repo, nil
+}
+
+// worktree opens the repository and its working tree.
+func (c *Client) worktree() (*gogit.Repository, *gogit.Worktree, error) {
+	repo, err := c.open()
+	if err != nil {
+		return nil, nil, err
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening worktree of %s: %w", c.Path, err)
+	}
+	return repo, wt, nil
+}
+
+// IsRepository reports whether the client's Path is the root of a git working tree.
+func (c *Client) IsRepository() bool {
+	_, err := os.Stat(filepath.Join(c.Path, ".git"))
+	return err == nil
+}
+
 // InitializeGitRepo ensures the client's Path is an empty directory (creates it if missing)
-// and initializes an empty git repository there. It does not add any remotes/origins.
+// and initializes an empty git repository there on DefaultBranch. It does not add any remotes/origins.
 func (c *Client) InitializeGitRepo() error {
 	path := c.Path
 	info, err := os.Stat(path)
@@ -48,35 +97,41 @@ func (c *Client) InitializeGitRepo() error {
 		return fmt.Errorf("stat %s: %w", path, err)
 	}
 
-	cmd := exec.Command("git", "init")
-	cmd.Dir = path
-	out, err := cmd.CombinedOutput()
+	_, err = gogit.PlainInitWithOptions(path, &gogit.PlainInitOptions{
+		InitOptions: gogit.InitOptions{DefaultBranch: plumbing.NewBranchReferenceName(DefaultBranch)},
+	})
 	if err != nil {
-		return fmt.Errorf("git init failed: %v: %s", err, string(out))
+		return fmt.Errorf("git init failed: %w", err)
 	}
 
 	return nil
 }
 
-// CommitAll stages all changes under the client's Path and creates a commit with the
-// provided message. The path must be a git repository working tree.
+// CommitAll stages all changes under the client's Path (like `git add --all`)
+// and creates a commit with the provided message, authored by AuthorName.
+// It returns ErrNothingToCommit when there is nothing to commit.
 func (c *Client) CommitAll(message string) error {
-	path := c.Path
-	// git add --all
-	cmd := exec.Command("git", "add", "--all")
-	cmd.Dir = path
-	out, err := cmd.CombinedOutput()
+	_, wt, err := c.worktree()
 	if err != nil {
-		return fmt.Errorf("git add failed: %v: %s", err, string(out))
+		return err
 	}
-
-	// git commit -m <message>
-	cmd = exec.Command("git", "commit", "-m", message)
-	cmd.Dir = path
-	out, err = cmd.CombinedOutput()
+	if err := wt.AddWithOptions(&gogit.AddOptions{All: true}); err != nil {
+		return fmt.Errorf("git add failed: %w", err)
+	}
+	status, err := wt.Status()
 	if err != nil {
-		// If there's nothing to commit, git returns exit code 1 with message on stdout/stderr.
-		return fmt.Errorf("git commit failed: %v: %s", err, string(out))
+		return fmt.Errorf("git status failed: %w", err)
+	}
+	if status.IsClean() {
+		return ErrNothingToCommit
+	}
+
+	signature := &object.Signature{Name: AuthorName, Email: AuthorEmail, When: time.Now()}
+	if _, err := wt.Commit(message, &gogit.CommitOptions{Author: signature, Committer: signature}); err != nil {
+		if errors.Is(err, gogit.ErrEmptyCommit) {
+			return ErrNothingToCommit
+		}
+		return fmt.Errorf("git commit failed: %w", err)
 	}
 
 	return nil
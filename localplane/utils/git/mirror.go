@@ -0,0 +1,80 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	gogit "github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+)
+
+// Auth holds the credentials of a remote repository. Password may be a
+// token; SSHPrivateKey (PEM) is used for ssh URLs. Empty means anonymous.
+type Auth struct {
+	Username      string
+	Password      string
+	SSHPrivateKey string
+}
+
+// method returns the go-git auth method for auth, nil when anonymous.
+func (a Auth) method() (transport.AuthMethod, error) {
+	switch {
+	case a.SSHPrivateKey != "":
+		user := a.Username
+		if user == "" {
+			user = "git"
+		}
+		keys, err := ssh.NewPublicKeys(user, []byte(a.SSHPrivateKey), "")
+		if err != nil {
+			return nil, fmt.Errorf("parsing ssh private key: %w", err)
+		}
+		return keys, nil
+	case a.Password != "":
+		user := a.Username
+		if user == "" {
+			// token-only credentials: most forges accept any non-empty user
+			user = "git"
+		}
+		return &http.BasicAuth{Username: user, Password: a.Password}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// Mirror creates a bare mirror of url at path (like `git clone --mirror`),
+// or updates all its refs when it already exists.
+func Mirror(ctx context.Context, path, url string, auth Auth) error {
+	method, err := auth.method()
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if _, err := gogit.PlainCloneContext(ctx, path, true, &gogit.CloneOptions{URL: url, Auth: method, Mirror: true}); err != nil {
+			return fmt.Errorf("mirroring %s: %w", url, err)
+		}
+		return nil
+	}
+
+	repo, err := gogit.PlainOpen(path)
+	if err != nil {
+		return fmt.Errorf("opening mirror %s: %w", path, err)
+	}
+	err = repo.FetchContext(ctx, &gogit.FetchOptions{
+		RemoteName: gogit.DefaultRemoteName,
+		RemoteURL:  url,
+		RefSpecs:   []gitconfig.RefSpec{"+refs/*:refs/*"},
+		Auth:       method,
+		Force:      true,
+		Prune:      true,
+	})
+	if err != nil && !errors.Is(err, gogit.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("updating mirror of %s: %w", url, err)
+	}
+	return nil
+}
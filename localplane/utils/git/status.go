@@ -0,0 +1,259 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	fdiff "github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/utils/diff"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// FileStatus is the status of a changed file, with the two letter code of
+// `git status --porcelain` (staging then worktree, `??` for untracked).
+type FileStatus struct {
+	Path string
+	Code string
+}
+
+// Commit summarizes a commit for Log.
+type Commit struct {
+	Hash    string
+	Author  string
+	When    time.Time
+	Message string
+}
+
+// Status returns the changed and untracked files, sorted by path. It is
+// empty when the working tree is clean.
+func (c *Client) Status() ([]FileStatus, error) {
+	_, wt, err := c.worktree()
+	if err != nil {
+		return nil, err
+	}
+	status, err := wt.Status()
+	if err != nil {
+		return nil, fmt.Errorf("git status failed: %w", err)
+	}
+	files := make([]FileStatus, 0, len(status))
+	for path, s := range status {
+		if s.Staging == gogit.Unmodified && s.Worktree == gogit.Unmodified {
+			continue
+		}
+		files = append(files, FileStatus{Path: path, Code: string([]byte{byte(s.Staging), byte(s.Worktree)})})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// Log returns up to max commits reachable from HEAD, newest first. A max of
+// 0 or less returns all of them. A repository without commits has no log.
+func (c *Client) Log(max int) ([]Commit, error) {
+	repo, err := c.open()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := repo.Head(); errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, nil
+	}
+	iter, err := repo.Log(&gogit.LogOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+	defer iter.Close()
+	var commits []Commit
+	for max <= 0 || len(commits) < max {
+		commit, err := iter.Next()
+		if err != nil {
+			break
+		}
+		commits = append(commits, Commit{
+			Hash:    commit.Hash.String(),
+			Author:  commit.Author.Name,
+			When:    commit.Author.When,
+			Message: strings.TrimSpace(commit.Message),
+		})
+	}
+	return commits, nil
+}
+
+// Diff returns the unified diff between HEAD and the working tree for the
+// tracked files, like `git diff HEAD`. Untracked files are not included.
+func (c *Client) Diff() (string, error) {
+	repo, wt, err := c.worktree()
+	if err != nil {
+		return "", err
+	}
+	status, err := wt.Status()
+	if err != nil {
+		return "", fmt.Errorf("git status failed: %w", err)
+	}
+
+	var tree *object.Tree
+	if head, err := repo.Head(); err == nil {
+		commit, err := repo.CommitObject(head.Hash())
+		if err != nil {
+			return "", fmt.Errorf("reading HEAD commit: %w", err)
+		}
+		if tree, err = commit.Tree(); err != nil {
+			return "", fmt.Errorf("reading HEAD tree: %w", err)
+		}
+	}
+
+	paths := make([]string, 0, len(status))
+	for path, s := range status {
+		if s.Worktree == gogit.Untracked || (s.Staging == gogit.Unmodified && s.Worktree == gogit.Unmodified) {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	patch := worktreePatch{}
+	for _, path := range paths {
+		var from, to *diffFile
+		if tree != nil {
+			if f, err := tree.File(path); err == nil {
+				content, err := f.Contents()
+				if err != nil {
+					return "", fmt.Errorf("reading %s at HEAD: %w", path, err)
+				}
+				from = &diffFile{path: path, mode: f.Mode, content: content}
+			}
+		}
+		if data, err := os.ReadFile(filepath.Join(c.Path, path)); err == nil {
+			mode := filemode.Regular
+			if from != nil {
+				mode = from.mode
+			}
+			to = &diffFile{path: path, mode: mode, content: string(data)}
+		} else if !os.IsNotExist(err) {
+			return "", fmt.Errorf("reading %s: %w", path, err)
+		}
+		if from == nil && to == nil {
+			continue
+		}
+		patch.files = append(patch.files, newFilePatch(from, to))
+	}
+
+	return encodePatch(patch)
+}
+
+// FileChange is a file before (From) and after (To) a change. A nil side
+// means the file doesn't exist there.
+type FileChange struct {
+	Path     string
+	From, To *string
+}
+
+// DiffFiles returns the unified diff of changes, in the format of Diff. It
+// needs no repository.
+func DiffFiles(changes []FileChange) (string, error) {
+	patch := worktreePatch{}
+	for _, ch := range changes {
+		var from, to *diffFile
+		if ch.From != nil {
+			from = &diffFile{path: ch.Path, mode: filemode.Regular, content: *ch.From}
+		}
+		if ch.To != nil {
+			to = &diffFile{path: ch.Path, mode: filemode.Regular, content: *ch.To}
+		}
+		if from == nil && to == nil {
+			continue
+		}
+		patch.files = append(patch.files, newFilePatch(from, to))
+	}
+	return encodePatch(patch)
+}
+
+func encodePatch(patch worktreePatch) (string, error) {
+	var buf bytes.Buffer
+	if err := fdiff.NewUnifiedEncoder(&buf, fdiff.DefaultContextLines).Encode(patch); err != nil {
+		return "", fmt.Errorf("encoding diff: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// worktreePatch implements the go-git diff.Patch interface for Diff.
+type worktreePatch struct {
+	files []fdiff.FilePatch
+}
+
+func (p worktreePatch) FilePatches() []fdiff.FilePatch { return p.files }
+func (p worktreePatch) Message() string                { return "" }
+
+type diffFile struct {
+	path    string
+	mode    filemode.FileMode
+	content string
+}
+
+func (f *diffFile) Hash() plumbing.Hash {
+	return plumbing.ComputeHash(plumbing.BlobObject, []byte(f.content))
+}
+func (f *diffFile) Mode() filemode.FileMode { return f.mode }
+func (f *diffFile) Path() string            { return f.path }
+
+type diffChunk struct {
+	content string
+	op      fdiff.Operation
+}
+
+func (c diffChunk) Content() string       { return c.content }
+func (c diffChunk) Type() fdiff.Operation { return c.op }
+
+type filePatch struct {
+	from, to *diffFile
+	binary   bool
+	chunks   []fdiff.Chunk
+}
+
+func newFilePatch(from, to *diffFile) *filePatch {
+	p := &filePatch{from: from, to: to}
+	var src, dst string
+	if from != nil {
+		src = from.content
+	}
+	if to != nil {
+		dst = to.content
+	}
+	if strings.ContainsRune(src, 0) || strings.ContainsRune(dst, 0) {
+		p.binary = true
+		return p
+	}
+	for _, d := range diff.Do(src, dst) {
+		op := fdiff.Equal
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			op = fdiff.Add
+		case diffmatchpatch.DiffDelete:
+			op = fdiff.Delete
+		}
+		p.chunks = append(p.chunks, diffChunk{content: d.Text, op: op})
+	}
+	return p
+}
+
+func (p *filePatch) IsBinary() bool { return p.binary }
+func (p *filePatch) Files() (fdiff.File, fdiff.File) {
+	// a nil *diffFile must become a nil interface for the encoder
+	var from, to fdiff.File
+	if p.from != nil {
+		from = p.from
+	}
+	if p.to != nil {
+		to = p.to
+	}
+	return from, to
+}
+func (p *filePatch) Chunks() []fdiff.Chunk { return p.chunks }
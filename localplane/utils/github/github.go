@@ -2,8 +2,6 @@ package github
 
 import (
 	"context"
-	"encoding/base64"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -11,27 +9,37 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+
+	"localplane/utils/retry"
+
+	"github.com/rs/zerolog/log"
 )
 
-type contentItem struct {
-	Type        string `json:"type"`
-	Name        string `json:"name"`
-	Path        string `json:"path"`
-	DownloadURL string `json:"download_url"`
-	URL         string `json:"url"`
-	Content     string `json:"content"`
-	Encoding    string `json:"encoding"`
-}
+// TokenEnv is the environment variable read for a GitHub token when the
+// Client has none. Authenticated requests get a much higher rate limit and
+// can read private repositories.
+const TokenEnv = "GITHUB_TOKEN"
+
+const apiURL = "https://api.github.com"
+
+var shaRegexp = regexp.MustCompile(`^[0-9a-f]{40}$`)
 
 // Client represents a GitHub repository access object. Optional fields are
-// pointer types and can be nil when not set.
+// pointer types and can be nil when not set. When CacheDir is set, the
+// downloaded tarballs are kept there by commit SHA and reused, so a ref
+// already downloaded once works offline.
 type Client struct {
 	Owner      string
 	Repo       string
 	Ref        *string
 	Token      *string
+	CacheDir   string
 	HTTPClient *http.Client
+	// Retry is the backoff of the requests failing with a network error or
+	// a 5xx / 429 status.
+	Retry retry.Policy
 }
 
 // NewClient creates a minimal Client with required owner and repo.
@@ -40,149 +48,190 @@ func NewClient(owner, repo string) *Client {
 }
 
 // DownloadPath downloads the file or directory at `srcPath` from the GitHub
-// repository into `destDir`, preserving relative paths. Optional fields on the
-// Client (Ref, Token) may be nil.
+// repository into `destDir`, preserving relative paths. The whole repository
+// is fetched as a single tarball of the resolved commit and only `srcPath` is
+// extracted. Optional fields on the Client (Ref, Token) may be nil.
 func (c *Client) DownloadPath(ctx context.Context, srcPath, destDir string) error {
 	if c == nil || c.Owner == "" || c.Repo == "" {
 		return errors.New("owner and repo are required on client")
 	}
-	srcPath = strings.TrimPrefix(srcPath, "/")
+	srcPath = strings.Trim(srcPath, "/")
 
-	client := c.HTTPClient
-	if client == nil {
-		client = &http.Client{}
+	sha, err := c.resolveRef(ctx)
+	if err != nil {
+		return err
+	}
+	tarball, cleanup, err := c.tarball(ctx, sha)
+	if err != nil {
+		return err
 	}
+	defer cleanup()
 
-	var fetch func(apiPath, basePath string) error
-	fetch = func(apiPath, basePath string) error {
-		apiPathEscaped := url.PathEscape(apiPath)
-		apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/contents/%s", c.Owner, c.Repo, apiPathEscaped)
-		if c.Ref != nil && *c.Ref != "" {
-			apiURL = apiURL + "?ref=" + url.QueryEscape(*c.Ref)
-		}
+	return extractPath(tarball, srcPath, destDir)
+}
 
-		req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
-		if err != nil {
-			return err
-		}
-		if c.Token != nil && *c.Token != "" {
-			req.Header.Set("Authorization", "token "+*c.Token)
-		}
-		req.Header.Set("Accept", "application/vnd.github.v3+json")
+// ref returns the requested ref, the default branch (HEAD) when unset.
+func (c *Client) ref() string {
+	if c.Ref != nil && *c.Ref != "" {
+		return *c.Ref
+	}
+	return "HEAD"
+}
 
-		resp, err := client.Do(req)
-		if err != nil {
-			return err
-		}
-		defer resp.Body.Close()
+// token returns the Client token, or the one from TokenEnv.
+func (c *Client) token() string {
+	if c.Token != nil && *c.Token != "" {
+		return *c.Token
+	}
+	return os.Getenv(TokenEnv)
+}
 
-		if resp.StatusCode == http.StatusNotFound {
-			return fmt.Errorf("path not found: %s", apiPath)
-		}
-		if resp.StatusCode >= 400 {
-			b, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("github api error: %s: %s", resp.Status, string(b))
-		}
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
 
-		body, err := io.ReadAll(resp.Body)
+// repoCacheDir returns the cache directory of the repository, empty when
+// caching is disabled.
+func (c *Client) repoCacheDir() string {
+	if c.CacheDir == "" {
+		return ""
+	}
+	return filepath.Join(c.CacheDir, c.Owner, c.Repo)
+}
+
+// get performs an authenticated GET on the GitHub API, retrying transient
+// failures with the Retry policy.
+func (c *Client) get(ctx context.Context, u, accept string) (*http.Response, error) {
+	var resp *http.Response
+	err := retry.Do(ctx, c.Retry, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
 		if err != nil {
-			return err
+			return retry.Permanent(err)
 		}
-
-		var arr []contentItem
-		if err := json.Unmarshal(body, &arr); err == nil {
-			for _, it := range arr {
-				if err := fetch(it.Path, basePath); err != nil {
-					return err
-				}
-			}
-			return nil
+		req.Header.Set("Accept", accept)
+		req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+		if token := c.token(); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
 		}
-
-		var it contentItem
-		if err := json.Unmarshal(body, &it); err != nil {
-			return fmt.Errorf("unable to parse github response: %w", err)
+		if resp, err = c.httpClient().Do(req); err != nil {
+			return err
 		}
-
-		if it.Type == "dir" {
-			req2, _ := http.NewRequestWithContext(ctx, "GET", it.URL, nil)
-			if c.Token != nil && *c.Token != "" {
-				req2.Header.Set("Authorization", "token "+*c.Token)
-			}
-			req2.Header.Set("Accept", "application/vnd.github.v3+json")
-			resp2, err := client.Do(req2)
-			if err != nil {
-				return err
-			}
-			defer resp2.Body.Close()
-			if resp2.StatusCode >= 400 {
-				b, _ := io.ReadAll(resp2.Body)
-				return fmt.Errorf("github api error: %s: %s", resp2.Status, string(b))
-			}
-			body2, _ := io.ReadAll(resp2.Body)
-			var arr2 []contentItem
-			if err := json.Unmarshal(body2, &arr2); err != nil {
-				return err
-			}
-			for _, child := range arr2 {
-				if err := fetch(child.Path, basePath); err != nil {
-					return err
-				}
-			}
+		if resp.StatusCode < 400 {
 			return nil
 		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		switch {
+		case resp.StatusCode == http.StatusNotFound:
+			return retry.Permanent(fmt.Errorf("github: %s/%s@%s not found (private repository without %s?)", c.Owner, c.Repo, c.ref(), TokenEnv))
+		case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+			return fmt.Errorf("github api error: %s: %s", resp.Status, strings.TrimSpace(string(b)))
+		default:
+			return retry.Permanent(fmt.Errorf("github api error: %s: %s", resp.Status, strings.TrimSpace(string(b))))
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
 
-		if it.Type == "file" || it.Type == "symlink" {
-			rel := strings.TrimPrefix(it.Path, strings.TrimPrefix(srcPath, "/"))
-			rel = strings.TrimPrefix(rel, "/")
-			destPath := filepath.Join(destDir, rel)
-			if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
-				return err
-			}
+// resolveRef returns the commit SHA of the requested ref. When GitHub can't
+// be reached, the SHA the ref last resolved to is used from the cache.
+func (c *Client) resolveRef(ctx context.Context) (string, error) {
+	ref := c.ref()
+	if shaRegexp.MatchString(ref) {
+		return ref, nil
+	}
+	cacheDir := c.repoCacheDir()
+	refFile := ""
+	if cacheDir != "" {
+		refFile = filepath.Join(cacheDir, "refs", url.PathEscape(ref))
+	}
 
-			if it.DownloadURL != "" {
-				reqf, _ := http.NewRequestWithContext(ctx, "GET", it.DownloadURL, nil)
-				if c.Token != nil && *c.Token != "" {
-					reqf.Header.Set("Authorization", "token "+*c.Token)
-				}
-				respf, err := client.Do(reqf)
-				if err != nil {
-					return err
-				}
-				defer respf.Body.Close()
-				if respf.StatusCode >= 400 {
-					b, _ := io.ReadAll(respf.Body)
-					return fmt.Errorf("download error: %s: %s", respf.Status, string(b))
-				}
-				out, err := os.Create(destPath)
-				if err != nil {
-					return err
-				}
-				defer out.Close()
-				if _, err := io.Copy(out, respf.Body); err != nil {
-					return err
-				}
-				return nil
+	u := fmt.Sprintf("%s/repos/%s/%s/commits/%s", apiURL, c.Owner, c.Repo, url.PathEscape(ref))
+	resp, err := c.get(ctx, u, "application/vnd.github.sha")
+	if err != nil {
+		if refFile != "" {
+			if b, readErr := os.ReadFile(refFile); readErr == nil && shaRegexp.MatchString(strings.TrimSpace(string(b))) {
+				sha := strings.TrimSpace(string(b))
+				log.Warn().Err(err).Str("ref", ref).Str("sha", sha).Msg("failed to resolve ref on GitHub; using cached commit")
+				return sha, nil
 			}
+		}
+		return "", fmt.Errorf("resolving %s/%s@%s: %w", c.Owner, c.Repo, ref, err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 128))
+	if err != nil {
+		return "", fmt.Errorf("resolving %s/%s@%s: %w", c.Owner, c.Repo, ref, err)
+	}
+	sha := strings.TrimSpace(string(b))
+	if !shaRegexp.MatchString(sha) {
+		return "", fmt.Errorf("resolving %s/%s@%s: unexpected response %q", c.Owner, c.Repo, ref, sha)
+	}
 
-			if it.Content != "" && it.Encoding == "base64" {
-				decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(it.Content, "\n", ""))
-				if err != nil {
-					return err
-				}
-				if err := os.WriteFile(destPath, decoded, 0o644); err != nil {
-					return err
-				}
-				return nil
-			}
+	if refFile != "" {
+		if err := os.MkdirAll(filepath.Dir(refFile), 0o755); err == nil {
+			_ = os.WriteFile(refFile, []byte(sha+"\n"), 0o644)
+		}
+	}
+	return sha, nil
+}
 
-			return fmt.Errorf("file has no download data: %s", it.Path)
+// tarball returns the path of the tarball of commit sha, downloading it when
+// it isn't cached. cleanup removes it when caching is disabled.
+func (c *Client) tarball(ctx context.Context, sha string) (string, func(), error) {
+	cacheDir := c.repoCacheDir()
+	if cacheDir != "" {
+		path := filepath.Join(cacheDir, sha+".tar.gz")
+		if _, err := os.Stat(path); err == nil {
+			log.Debug().Str("path", path).Msg("using cached GitHub tarball")
+			return path, func() {}, nil
 		}
+	}
 
-		return fmt.Errorf("unsupported content type: %s", it.Type)
+	dir := cacheDir
+	if dir == "" {
+		dir = os.TempDir()
+	} else if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", nil, fmt.Errorf("creating cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, sha+".*.tmp")
+	if err != nil {
+		return "", nil, err
+	}
+	defer tmp.Close()
+
+	u := fmt.Sprintf("%s/repos/%s/%s/tarball/%s", apiURL, c.Owner, c.Repo, sha)
+	log.Debug().Str("url", u).Msg("downloading GitHub tarball")
+	resp, err := c.get(ctx, u, "application/vnd.github+json")
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", nil, fmt.Errorf("downloading %s/%s@%s: %w", c.Owner, c.Repo, sha, err)
+	}
+	defer resp.Body.Close()
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		os.Remove(tmp.Name())
+		return "", nil, fmt.Errorf("downloading %s/%s@%s: %w", c.Owner, c.Repo, sha, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", nil, err
 	}
 
-	return fetch(srcPath, srcPath)
+	if cacheDir == "" {
+		return tmp.Name(), func() { os.Remove(tmp.Name()) }, nil
+	}
+	path := filepath.Join(cacheDir, sha+".tar.gz")
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return "", nil, fmt.Errorf("caching tarball: %w", err)
+	}
+	return path, func() {}, nil
 }
 
 // DownloadRepoPath is a convenience wrapper that preserves the previous
@@ -0,0 +1,174 @@
+package github
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// extractPath extracts srcPath (a file or a directory, relative to the
+// repository root) from the GitHub tarball at tarball into destDir. GitHub
+// prefixes every entry with a `<owner>-<repo>-<sha>/` directory, which is
+// stripped. When srcPath is a file, it is written to destDir itself. The
+// files are extracted next to destDir first and moved in place once complete,
+// so a broken tarball doesn't leave a partial tree behind.
+func extractPath(tarball, srcPath, destDir string) error {
+	f, err := os.Open(tarball)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("reading tarball %s: %w", tarball, err)
+	}
+	defer gz.Close()
+
+	if err := os.MkdirAll(filepath.Dir(destDir), 0o755); err != nil {
+		return err
+	}
+	tmpDir, err := os.MkdirTemp(filepath.Dir(destDir), "."+filepath.Base(destDir)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	staging := filepath.Join(tmpDir, "out")
+
+	found := false
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("reading tarball %s: %w", tarball, err)
+		}
+
+		name := hdr.Name
+		if i := strings.Index(name, "/"); i >= 0 {
+			name = name[i+1:]
+		} else {
+			continue
+		}
+		name = strings.TrimSuffix(path.Clean("/"+name), "/")
+		name = strings.TrimPrefix(name, "/")
+
+		rel, ok := relativeTo(name, srcPath)
+		if !ok {
+			continue
+		}
+		found = true
+		target := filepath.Join(staging, filepath.FromSlash(rel))
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFile(target, tr, hdr.FileInfo().Mode().Perm()|0o644); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if filepath.IsAbs(hdr.Linkname) || escapes(path.Join(path.Dir(rel), hdr.Linkname)) {
+				return fmt.Errorf("refusing symlink %s pointing outside of %s", name, srcPath)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			if err := os.Symlink(hdr.Linkname, target); err != nil {
+				return err
+			}
+		}
+	}
+	if !found {
+		return fmt.Errorf("path not found: %s", srcPath)
+	}
+
+	return moveTree(staging, destDir)
+}
+
+// relativeTo returns name relative to dir (both slash separated, relative
+// to the repository root) and whether name is dir or inside it.
+func relativeTo(name, dir string) (string, bool) {
+	switch {
+	case dir == "":
+		return name, true
+	case name == dir:
+		return "", true
+	case strings.HasPrefix(name, dir+"/"):
+		return strings.TrimPrefix(name, dir+"/"), true
+	default:
+		return "", false
+	}
+}
+
+func writeFile(target string, r io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// moveTree moves src to dest. When dest is an existing directory, the files
+// of src are moved into it, replacing existing files.
+func moveTree(src, dest string) error {
+	info, err := os.Stat(dest)
+	if os.IsNotExist(err) {
+		if err := os.Rename(src, dest); err != nil {
+			return fmt.Errorf("moving downloaded files to %s: %w", dest, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		if err := os.Rename(src, dest); err != nil {
+			return fmt.Errorf("moving downloaded files to %s: %w", dest, err)
+		}
+		return nil
+	}
+	return filepath.WalkDir(src, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		if err := os.RemoveAll(target); err != nil {
+			return err
+		}
+		if err := os.Rename(p, target); err != nil {
+			return fmt.Errorf("moving downloaded files to %s: %w", dest, err)
+		}
+		return nil
+	})
+}
+
+// escapes reports whether the slash separated relative path p points above
+// its root.
+func escapes(p string) bool {
+	p = path.Clean(p)
+	return p == ".." || strings.HasPrefix(p, "../")
+}
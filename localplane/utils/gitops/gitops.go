@@ -0,0 +1,176 @@
+package gitops
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"localplane/utils/argocd"
+	"localplane/utils/flux"
+	"localplane/utils/kubectl"
+	"localplane/utils/remoterepo"
+	"localplane/utils/retry"
+)
+
+const (
+	// EngineArgoCD reconciles the local-argo repo with ArgoCD (default).
+	EngineArgoCD = "argocd"
+	// EngineFlux reconciles the local-argo repo with Flux.
+	EngineFlux = "flux"
+	// EngineNone skips GitOps setup entirely.
+	EngineNone = "none"
+
+	// DefaultEngine is used when no engine is configured.
+	DefaultEngine = EngineArgoCD
+)
+
+// Engine installs a GitOps controller into a cluster and knows which
+// bootstrap manifests of the workspace chart wire it to the local-argo repo.
+type Engine interface {
+	// Name returns the engine identifier (EngineArgoCD, EngineFlux).
+	Name() string
+	// Install installs or upgrades the engine and returns a short summary.
+	Install(ctx context.Context) (string, error)
+	// BootstrapPatterns returns the glob patterns, relative to bootstrapDir,
+	// of the manifests to apply once the engine is installed.
+	BootstrapPatterns(bootstrapDir string) []string
+	// TrackBranch points the bootstrapped source at branch of the local-argo
+	// repo. It must be called after the bootstrap manifests are applied.
+	TrackBranch(ctx context.Context, branch string) error
+	// Refresh asks the engine to pick up new commits of the local-argo repo
+	// now for the objects sourced from the changed paths (relative to the
+	// repo root), and returns what it refreshed.
+	Refresh(ctx context.Context, changed []string) ([]string, error)
+	// RegisterRepositories makes the remote repositories available to the
+	// engine, and deploys the ones with a path.
+	RegisterRepositories(ctx context.Context, repos []remoterepo.Repository) error
+}
+
+// Options configures the engines. Only the fields of the selected engine
+// are used.
+type Options struct {
+	Kubeconfig string
+	// Retry is the backoff of the kubectl calls failing with a transient
+	// error.
+	Retry retry.Policy
+	// ArgoCD options
+	ArgoCDMounts  []argocd.RepoMount
+	ArgoCDInstall argocd.InstallOptions
+	// Flux options
+	FluxInstall flux.InstallOptions
+}
+
+// kubectl returns the kubectl client of the cluster of o.
+func (o Options) kubectl() *kubectl.Client {
+	client := kubectl.NewClient(&o.Kubeconfig, nil)
+	client.Retry = o.Retry
+	return client
+}
+
+// argocd returns the ArgoCD client of the cluster of o.
+func (o Options) argocd() *argocd.Client {
+	client := argocd.NewClient(o.Kubeconfig)
+	client.Retry = o.Retry
+	return client
+}
+
+// New returns the Engine for name. It returns nil and no error for
+// EngineNone; an empty name resolves to DefaultEngine.
+func New(name string, opts Options) (Engine, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", EngineArgoCD:
+		return &argoCDEngine{opts: opts}, nil
+	case EngineFlux:
+		return &fluxEngine{opts: opts}, nil
+	case EngineNone:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unsupported gitops engine %q (supported: %s)", name, strings.Join(Engines(), ", "))
+	}
+}
+
+// Engines returns the supported engine names.
+func Engines() []string {
+	return []string{EngineArgoCD, EngineFlux, EngineNone}
+}
+
+type argoCDEngine struct {
+	opts Options
+}
+
+func (e *argoCDEngine) Name() string { return EngineArgoCD }
+
+func (e *argoCDEngine) Install(ctx context.Context) (string, error) {
+	return e.opts.argocd().InstallOrUpgradeArgoCD(ctx, e.opts.ArgoCDMounts, e.opts.ArgoCDInstall)
+}
+
+func (e *argoCDEngine) BootstrapPatterns(bootstrapDir string) []string {
+	return []string{filepath.Join(bootstrapDir, "argo-bootstrap-*.yaml")}
+}
+
+func (e *argoCDEngine) TrackBranch(ctx context.Context, branch string) error {
+	patch := fmt.Sprintf(`{"spec":{"source":{"targetRevision":%q}}}`, branch)
+	return e.opts.kubectl().Patch(ctx, "applications.argoproj.io", "local-stack-bootstrap", argocd.Namespace, patch)
+}
+
+func (e *argoCDEngine) Refresh(ctx context.Context, changed []string) ([]string, error) {
+	client := e.opts.argocd()
+	apps, err := client.ListApplications(ctx)
+	if err != nil {
+		return nil, err
+	}
+	affected := argocd.AffectedApplications(apps, argocd.LocalRepoURL, changed)
+	for _, name := range affected {
+		if err := client.RefreshApplication(ctx, name); err != nil {
+			return nil, err
+		}
+	}
+	return affected, nil
+}
+
+func (e *argoCDEngine) RegisterRepositories(ctx context.Context, repos []remoterepo.Repository) error {
+	manifest, err := argocd.RepositoryManifests(repos)
+	if err != nil {
+		return err
+	}
+	return e.opts.kubectl().ApplyManifest(ctx, manifest)
+}
+
+type fluxEngine struct {
+	opts Options
+}
+
+func (e *fluxEngine) Name() string { return EngineFlux }
+
+func (e *fluxEngine) Install(ctx context.Context) (string, error) {
+	return flux.NewClient(e.opts.Kubeconfig).InstallOrUpgradeFlux(ctx, e.opts.FluxInstall)
+}
+
+func (e *fluxEngine) BootstrapPatterns(bootstrapDir string) []string {
+	return []string{filepath.Join(bootstrapDir, "flux-bootstrap-*.yaml")}
+}
+
+func (e *fluxEngine) TrackBranch(ctx context.Context, branch string) error {
+	patch := fmt.Sprintf(`{"spec":{"ref":{"branch":%q}}}`, branch)
+	return e.opts.kubectl().Patch(ctx, "gitrepositories.source.toolkit.fluxcd.io", "local-argo", flux.Namespace, patch)
+}
+
+// Refresh requests a reconciliation of the local-argo GitRepository; the
+// HelmReleases built from it follow once the new revision is fetched.
+func (e *fluxEngine) Refresh(ctx context.Context, changed []string) ([]string, error) {
+	requestedAt := time.Now().UTC().Format(time.RFC3339Nano)
+	if err := e.opts.kubectl().Annotate(ctx, "gitrepositories.source.toolkit.fluxcd.io", "local-argo", flux.Namespace, "reconcile.fluxcd.io/requestedAt", requestedAt); err != nil {
+		return nil, fmt.Errorf("refreshing gitrepository local-argo: %w", err)
+	}
+	return []string{"gitrepository/local-argo"}, nil
+}
+
+func (e *fluxEngine) RegisterRepositories(ctx context.Context, repos []remoterepo.Repository) error {
+	manifest, err := flux.RepositoryManifests(repos)
+	if err != nil {
+		return err
+	}
+	return e.opts.kubectl().ApplyManifest(ctx, manifest)
+}
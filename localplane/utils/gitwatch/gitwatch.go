@@ -0,0 +1,122 @@
+package gitwatch
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultDebounce is how long the working tree must stay quiet before a
+// batch of changes is handed over, so an editor saving several files (or a
+// chart being copied) results in a single commit.
+const DefaultDebounce = 2 * time.Second
+
+// Client watches the working tree of a git repository rooted at Path.
+type Client struct {
+	Path     string
+	Debounce time.Duration
+}
+
+// NewClient creates a watcher for the repository at path. A zero debounce
+// uses DefaultDebounce.
+func NewClient(path string, debounce time.Duration) *Client {
+	if debounce <= 0 {
+		debounce = DefaultDebounce
+	}
+	return &Client{Path: path, Debounce: debounce}
+}
+
+// Watch watches the working tree recursively (the .git directory excluded)
+// and calls onChange with the changed paths, relative to Path and sorted,
+// once no event was seen for Debounce. Directories created later are
+// watched too. It blocks until ctx is done, then returns nil.
+func (c *Client) Watch(ctx context.Context, onChange func(ctx context.Context, changed []string)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("creating file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if err := c.addTree(watcher, c.Path); err != nil {
+		return err
+	}
+
+	pending := map[string]struct{}{}
+	timer := time.NewTimer(c.Debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Warn().Err(err).Str("path", c.Path).Msg("file watcher error")
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			rel, err := filepath.Rel(c.Path, event.Name)
+			if err != nil || c.ignored(rel) {
+				continue
+			}
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := c.addTree(watcher, event.Name); err != nil {
+						log.Warn().Err(err).Str("path", event.Name).Msg("failed to watch new directory")
+					}
+				}
+			}
+			log.Debug().Str("path", rel).Str("op", event.Op.String()).Msg("change detected")
+			pending[filepath.ToSlash(rel)] = struct{}{}
+			timer.Reset(c.Debounce)
+		case <-timer.C:
+			if len(pending) == 0 {
+				continue
+			}
+			changed := make([]string, 0, len(pending))
+			for p := range pending {
+				changed = append(changed, p)
+			}
+			sort.Strings(changed)
+			pending = map[string]struct{}{}
+			onChange(ctx, changed)
+		}
+	}
+}
+
+// addTree watches root and all the directories below it, skipping .git.
+func (c *Client) addTree(watcher *fsnotify.Watcher, root string) error {
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if rel, _ := filepath.Rel(c.Path, p); c.ignored(rel) {
+			return filepath.SkipDir
+		}
+		if err := watcher.Add(p); err != nil {
+			return fmt.Errorf("watching %s: %w", p, err)
+		}
+		return nil
+	})
+}
+
+// ignored reports whether rel (relative to Path) is inside the .git directory.
+func (c *Client) ignored(rel string) bool {
+	rel = filepath.ToSlash(rel)
+	return rel == ".git" || strings.HasPrefix(rel, ".git/")
+}
@@ -0,0 +1,143 @@
+package helm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"localplane/config"
+	stdlog "log"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+	clivalues "helm.sh/helm/v3/pkg/cli/values"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+// Client runs Helm SDK actions against the cluster selected by Kubeconfig.
+type Client struct {
+	Kubeconfig string
+}
+
+// NewClient creates a configured Client. Pass empty string for defaults.
+func NewClient(kubeconfig string) *Client {
+	return &Client{Kubeconfig: kubeconfig}
+}
+
+// Release describes what to install and where.
+type Release struct {
+	Name      string
+	Namespace string
+	// Chart is the chart name in RepoURL. Ignored when ChartPath is set.
+	Chart   string
+	RepoURL string
+	Version string
+	// ChartPath loads the chart from a local .tgz archive or directory.
+	ChartPath string
+	// Values are the built-in values; ValuesFiles are merged on top of them,
+	// later files taking precedence (like `helm -f a.yaml -f b.yaml`).
+	Values      map[string]interface{}
+	ValuesFiles []string
+	// Timeout bounds the wait for resources to become ready. Defaults to 5m.
+	Timeout time.Duration
+}
+
+// InstallOrUpgrade installs the release when missing and upgrades it
+// otherwise (helm upgrade --install), waiting for resources to be ready.
+// Cancelling ctx stops the wait. It returns a short human readable summary
+// of the resulting release.
+func (c *Client) InstallOrUpgrade(ctx context.Context, r Release) (string, error) {
+	settings := cli.New()
+	if c != nil && c.Kubeconfig != "" {
+		settings.KubeConfig = c.Kubeconfig
+	}
+	var cfg action.Configuration
+	var helmOutput = func(format string, v ...interface{}) { /* no-op */ }
+	if config.CliConfig.Debug {
+		helmOutput = stdlog.Printf
+	}
+	if err := cfg.Init(settings.RESTClientGetter(), r.Namespace, os.Getenv("HELM_DRIVER"), helmOutput); err != nil {
+		return "", fmt.Errorf("failed to init helm configuration: %w", err)
+	}
+
+	values := r.Values
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	// user values files override the built-in values
+	if len(r.ValuesFiles) > 0 {
+		vo := clivalues.Options{ValueFiles: r.ValuesFiles}
+		userValues, err := vo.MergeValues(getter.All(settings))
+		if err != nil {
+			return "", fmt.Errorf("read values files: %w", err)
+		}
+		values = chartutil.CoalesceTables(userValues, values)
+	}
+
+	// load the chart from a local path, or locate it in the repository
+	chartPath := r.ChartPath
+	if chartPath == "" {
+		cp := action.ChartPathOptions{RepoURL: r.RepoURL, Version: r.Version}
+		located, err := cp.LocateChart(r.Chart, settings)
+		if err != nil {
+			return "", fmt.Errorf("locate chart %s@%s: %w", r.Chart, r.Version, err)
+		}
+		chartPath = located
+	}
+	ch, err := loader.Load(chartPath)
+	if err != nil {
+		return "", fmt.Errorf("load chart %s: %w", chartPath, err)
+	}
+	log.Debug().Str("chart", ch.Metadata.Name).Str("version", ch.Metadata.Version).Str("path", chartPath).Msg("loaded chart")
+
+	timeout := r.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Minute
+	}
+
+	// Prepare variables to capture release name and version after install/upgrade.
+	var relName string
+	var relVersion int
+	// If not found -> install, else upgrade.
+	g := action.NewGet(&cfg)
+	_, err = g.Run(r.Name)
+	if err != nil {
+		// If the release is not found, perform an install.
+		if errors.Is(err, driver.ErrReleaseNotFound) {
+			i := action.NewInstall(&cfg)
+			i.ReleaseName = r.Name
+			i.Namespace = r.Namespace
+			i.CreateNamespace = true
+			i.Timeout = timeout
+			i.Wait = true
+			rel, err := i.RunWithContext(ctx, ch, values)
+			if err != nil {
+				return "", fmt.Errorf("install failed: %w", err)
+			}
+			relName = rel.Name
+			relVersion = rel.Version
+		} else {
+			return "", fmt.Errorf("failed checking release: %w", err)
+		}
+	} else {
+		// Release exists -> perform upgrade.
+		u := action.NewUpgrade(&cfg)
+		u.Namespace = r.Namespace
+		u.Timeout = timeout
+		u.Wait = true
+		rel, err := u.RunWithContext(ctx, r.Name, ch, values)
+		if err != nil {
+			return "", fmt.Errorf("upgrade failed: %w", err)
+		}
+		relName = rel.Name
+		relVersion = rel.Version
+	}
+
+	log.Info().Str("release", relName).Int("version", relVersion).Str("namespace", r.Namespace).Msg("release installed/updated via helm sdk")
+	return fmt.Sprintf("release %s (version %d)", relName, relVersion), nil
+}
@@ -0,0 +1,52 @@
+package helm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/registry"
+)
+
+// PullChart downloads chart (an oci:// reference, or a chart name in repoURL)
+// at version (latest when empty) and unpacks it into dest, like
+// `helm pull --untar`. Registry credentials come from `helm registry login`.
+func (c *Client) PullChart(chart, repoURL, version, dest string) error {
+	settings := cli.New()
+	regClient, err := registry.NewClient(registry.ClientOptCredentialsFile(settings.RegistryConfig))
+	if err != nil {
+		return fmt.Errorf("failed to create registry client: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	tmpDir, err := os.MkdirTemp(filepath.Dir(dest), "."+filepath.Base(dest)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	pull := action.NewPullWithOpts(action.WithConfig(&action.Configuration{RegistryClient: regClient}))
+	pull.Settings = settings
+	pull.RepoURL = repoURL
+	pull.Version = version
+	pull.DestDir = tmpDir
+	pull.Untar = true
+	pull.UntarDir = filepath.Join(tmpDir, "chart")
+	if _, err := pull.Run(chart); err != nil {
+		return fmt.Errorf("pull chart %s@%s: %w", chart, version, err)
+	}
+
+	// the archive is unpacked into a directory named after the chart
+	entries, err := os.ReadDir(pull.UntarDir)
+	if err != nil || len(entries) != 1 || !entries[0].IsDir() {
+		return fmt.Errorf("pull chart %s@%s: unexpected archive layout", chart, version)
+	}
+	if err := os.Rename(filepath.Join(pull.UntarDir, entries[0].Name()), dest); err != nil {
+		return fmt.Errorf("moving chart to %s: %w", dest, err)
+	}
+	return nil
+}
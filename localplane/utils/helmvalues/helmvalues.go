@@ -0,0 +1,119 @@
+package helmvalues
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Set sets the dotted key (e.g. `localplane-addons.tls.enabled`) to value in
+// the YAML values file at path, creating intermediate maps as needed.
+// Comments and key order of the existing file are preserved. A missing file
+// is created.
+func Set(path, key string, value interface{}) error {
+	if key == "" {
+		return fmt.Errorf("key must be provided")
+	}
+
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading values file %s: %w", path, err)
+	}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("parsing values file %s: %w", path, err)
+		}
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("values file %s is not a YAML mapping", path)
+	}
+
+	var valueNode yaml.Node
+	if err := valueNode.Encode(value); err != nil {
+		return fmt.Errorf("encoding value for %s: %w", key, err)
+	}
+
+	parts := strings.Split(key, ".")
+	node := root
+	for i, part := range parts {
+		last := i == len(parts)-1
+		child := lookup(node, part)
+		if child == nil {
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: part})
+			if last {
+				node.Content = append(node.Content, &valueNode)
+				break
+			}
+			child = &yaml.Node{Kind: yaml.MappingNode}
+			node.Content = append(node.Content, child)
+		} else if last {
+			*child = valueNode
+			break
+		}
+		if child.Kind != yaml.MappingNode {
+			return fmt.Errorf("key %s in %s is not a mapping", strings.Join(parts[:i+1], "."), path)
+		}
+		node = child
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("encoding values file %s: %w", path, err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("encoding values file %s: %w", path, err)
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// GetString returns the scalar value of the dotted key in the YAML values
+// file at path. found is false when the file or the key doesn't exist.
+func GetString(path, key string) (value string, found bool, err error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("reading values file %s: %w", path, err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return "", false, fmt.Errorf("parsing values file %s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		return "", false, nil
+	}
+	node := doc.Content[0]
+	for _, part := range strings.Split(key, ".") {
+		if node.Kind != yaml.MappingNode {
+			return "", false, nil
+		}
+		if node = lookup(node, part); node == nil {
+			return "", false, nil
+		}
+	}
+	if node.Kind != yaml.ScalarNode {
+		return "", false, fmt.Errorf("%s in %s is not a scalar", key, path)
+	}
+	return node.Value, true, nil
+}
+
+// lookup returns the value node for key in the mapping node, or nil.
+func lookup(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
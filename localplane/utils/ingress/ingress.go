@@ -0,0 +1,95 @@
+package ingress
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// TypeHAProxy is the HAProxy Kubernetes ingress controller (default).
+	TypeHAProxy = "haproxy"
+	// TypeIngressNginx is the community ingress-nginx controller.
+	TypeIngressNginx = "ingress-nginx"
+	// TypeTraefik is the Traefik proxy used as an ingress controller.
+	TypeTraefik = "traefik"
+	// TypeGatewayAPI is Envoy Gateway serving the Gateway API instead of Ingress.
+	TypeGatewayAPI = "gateway-api"
+
+	// DefaultType is used when no ingress type is configured.
+	DefaultType = TypeHAProxy
+)
+
+// Controller describes where an ingress implementation installed by the
+// localplane-addons chart lives and how workloads should reference it.
+type Controller struct {
+	// Type is the value of `ingress.type` in the addons chart.
+	Type string
+	// ClassName is the IngressClass to set on Ingress objects. Empty for
+	// Gateway API based controllers.
+	ClassName string
+	// Namespace is where the controller and its LoadBalancer Service run.
+	Namespace string
+	// ServiceSelector is a label selector matching the controller's
+	// LoadBalancer Service.
+	ServiceSelector string
+	// Gateway is the name of the shared Gateway (Gateway API only).
+	Gateway string
+}
+
+// UsesGatewayAPI reports whether routes must be exposed with HTTPRoutes
+// attached to Gateway rather than with Ingress objects.
+func (c Controller) UsesGatewayAPI() bool {
+	return c.Gateway != ""
+}
+
+var controllers = map[string]Controller{
+	TypeHAProxy: {
+		Type:            TypeHAProxy,
+		ClassName:       "haproxy",
+		Namespace:       "ingress",
+		ServiceSelector: "app.kubernetes.io/name=kubernetes-ingress",
+	},
+	TypeIngressNginx: {
+		Type:            TypeIngressNginx,
+		ClassName:       "nginx",
+		Namespace:       "ingress-nginx",
+		ServiceSelector: "app.kubernetes.io/name=ingress-nginx,app.kubernetes.io/component=controller",
+	},
+	TypeTraefik: {
+		Type:            TypeTraefik,
+		ClassName:       "traefik",
+		Namespace:       "traefik",
+		ServiceSelector: "app.kubernetes.io/name=traefik",
+	},
+	TypeGatewayAPI: {
+		Type:            TypeGatewayAPI,
+		Namespace:       "envoy-gateway-system",
+		ServiceSelector: "gateway.envoyproxy.io/owning-gateway-name=localplane",
+		Gateway:         "localplane",
+	},
+}
+
+// Lookup returns the Controller for the given type. An empty type resolves to
+// DefaultType.
+func Lookup(ingressType string) (Controller, error) {
+	t := strings.ToLower(strings.TrimSpace(ingressType))
+	if t == "" {
+		t = DefaultType
+	}
+	c, ok := controllers[t]
+	if !ok {
+		return Controller{}, fmt.Errorf("unsupported ingress type %q (supported: %s)", ingressType, strings.Join(Types(), ", "))
+	}
+	return c, nil
+}
+
+// Types returns the supported ingress types, sorted.
+func Types() []string {
+	types := make([]string, 0, len(controllers))
+	for t := range controllers {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
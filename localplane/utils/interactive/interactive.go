@@ -0,0 +1,17 @@
+package interactive
+
+import (
+	"os"
+
+	"localplane/config"
+
+	"golang.org/x/term"
+)
+
+// Enabled reports whether the CLI may prompt the user: it runs on a terminal
+// (stdin is a TTY, so not in a CI pipeline) and --non-interactive wasn't set.
+// When it returns false, commands use their defaults or fail with a message
+// naming the flag to pass instead.
+func Enabled() bool {
+	return !config.CliConfig.NonInteractive && term.IsTerminal(int(os.Stdin.Fd()))
+}
@@ -36,11 +36,15 @@ func LoadKindConfig(path string) (*KindCluster, error) {
 	return cfg, nil
 }
 
+// AddExtraMount mounts host at container on every node. A mount already
+// targeting container is repointed at host, so re-running it for another
+// cluster doesn't leave two mounts on the same container path.
 func AddExtraMount(cfg *KindCluster, host, container string) {
 	for i := range cfg.Nodes {
 		exists := false
-		for _, m := range cfg.Nodes[i].ExtraMounts {
-			if m.HostPath == host && m.ContainerPath == container {
+		for j, m := range cfg.Nodes[i].ExtraMounts {
+			if m.ContainerPath == container {
+				cfg.Nodes[i].ExtraMounts[j].HostPath = host
 				exists = true
 				break
 			}
@@ -1,14 +1,17 @@
 package kind
 
 import (
+	"context"
 	"fmt"
 	"localplane/config"
+	"localplane/utils/retry"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/rs/zerolog/log"
 )
@@ -19,16 +22,32 @@ func isInstalled(name string) bool {
 	return err == nil
 }
 
+// interruptWait is how long a command interrupted by its context gets to
+// exit before it is killed.
+const interruptWait = 10 * time.Second
+
+// command returns a command interrupted (SIGINT, then SIGKILL after
+// interruptWait) when ctx is done, so kind gets a chance to clean up.
+func command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Cancel = func() error { return cmd.Process.Signal(os.Interrupt) }
+	cmd.WaitDelay = interruptWait
+	return cmd
+}
+
 // runCmd runs a command and returns combined stdout/stderr.
-func runCmd(name string, args ...string) (string, error) {
+func runCmd(ctx context.Context, name string, args ...string) (string, error) {
 	log.Debug().Str("cmd", name).Str("args", strings.Join(args, " ")).Msg("running command")
-	out, err := exec.Command(name, args...).CombinedOutput()
+	out, err := command(ctx, name, args...).CombinedOutput()
+	if ctx.Err() != nil {
+		return string(out), fmt.Errorf("%s interrupted: %w", name, context.Cause(ctx))
+	}
 	return string(out), err
 }
 
 // ensureCloudProviderKindInstalled ensures the `cloud-provider-kind` binary exists.
 // If missing and `go` is available, it will attempt `go install sigs.k8s.io/cloud-provider-kind@latest`.
-func ensureCloudProviderKindInstalled() error {
+func ensureCloudProviderKindInstalled(ctx context.Context) error {
 	if isInstalled("cloud-provider-kind") {
 		return nil
 	}
@@ -36,7 +55,7 @@ func ensureCloudProviderKindInstalled() error {
 		return fmt.Errorf("go not installed; cannot install cloud-provider-kind")
 	}
 
-	out, err := runCmd("go", "install", "sigs.k8s.io/cloud-provider-kind@latest")
+	out, err := runCmd(ctx, "go", "install", "sigs.k8s.io/cloud-provider-kind@latest")
 	if err != nil {
 		return fmt.Errorf("failed to install cloud-provider-kind: %w; output: %s", err, out)
 	}
@@ -51,6 +70,12 @@ func ensureCloudProviderKindInstalled() error {
 // operations such as a default kubeconfig path.
 type Client struct {
 	Kubeconfig string
+	// Directory holds the `clusters/<name>` directories where the load
+	// balancer state is kept (the CLI directory when empty).
+	Directory string
+	// Retry is the backoff of the failing `kind get clusters` and `kind
+	// delete cluster` calls; `kind create cluster` isn't retried.
+	Retry retry.Policy
 }
 
 // NewClient creates a Client. Pass empty string for defaults.
@@ -59,8 +84,9 @@ func NewClient(kubeconfig string) *Client {
 }
 
 // Create creates a kind cluster with the provided name. If configPath is non-empty
-// it will be passed to `kind create cluster --config`.
-func (c *Client) Create(name string, configPath string) error {
+// it will be passed to `kind create cluster --config`. Cancelling ctx
+// interrupts kind, which may leave a partially created cluster behind.
+func (c *Client) Create(ctx context.Context, name string, configPath string) error {
 	kubeconfigPath := c.Kubeconfig
 	if !isInstalled("kind") {
 		return fmt.Errorf("kind not installed")
@@ -70,7 +96,7 @@ func (c *Client) Create(name string, configPath string) error {
 	}
 
 	// ensure cloud-provider-kind is available (will attempt to install with `go install`)
-	if err := ensureCloudProviderKindInstalled(); err != nil {
+	if err := ensureCloudProviderKindInstalled(ctx); err != nil {
 		return err
 	}
 
@@ -79,7 +105,7 @@ func (c *Client) Create(name string, configPath string) error {
 		args = append(args, "--config", configPath)
 	}
 
-	out, err := runCmd("kind", args...)
+	out, err := runCmd(ctx, "kind", args...)
 	if err != nil {
 		return fmt.Errorf("failed to create kind cluster: %w; output: %s", err, out)
 	}
@@ -87,8 +113,38 @@ func (c *Client) Create(name string, configPath string) error {
 	return nil
 }
 
+// List returns the names of the existing kind clusters.
+func (c *Client) List(ctx context.Context) ([]string, error) {
+	if !isInstalled("kind") {
+		return nil, fmt.Errorf("kind not installed")
+	}
+	var out []byte
+	err := retry.Do(ctx, c.Retry, func() error {
+		var err error
+		out, err = command(ctx, "kind", "get", "clusters").Output()
+		return err
+	})
+	if ctx.Err() != nil {
+		return nil, context.Cause(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list kind clusters: %w", err)
+	}
+	names := []string{}
+	for _, l := range strings.Split(string(out), "\n") {
+		l = strings.TrimSpace(l)
+		// kind prints "No kind clusters found." on stderr, but older
+		// versions printed it on stdout
+		if l == "" || strings.HasPrefix(strings.ToLower(l), "no kind clusters") {
+			continue
+		}
+		names = append(names, l)
+	}
+	return names, nil
+}
+
 // Delete deletes a kind cluster by name.
-func (c *Client) Delete(name string) error {
+func (c *Client) Delete(ctx context.Context, name string) error {
 	if !isInstalled("kind") {
 		return fmt.Errorf("kind not installed")
 	}
@@ -96,7 +152,12 @@ func (c *Client) Delete(name string) error {
 		return fmt.Errorf("docker not installed")
 	}
 
-	out, err := runCmd("kind", "delete", "cluster", "--name", name)
+	var out string
+	err := retry.Do(ctx, c.Retry, func() error {
+		var err error
+		out, err = runCmd(ctx, "kind", "delete", "cluster", "--name", name)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to delete kind cluster: %w; output: %s", err, out)
 	}
@@ -107,11 +168,12 @@ func (c *Client) Delete(name string) error {
 // StartLoadBalancer starts the cloud-provider-kind process for the given cluster.
 // If background==true the process is started detached and logs are written to
 // a temp file; the function returns immediately while the process continues
-// running after the CLI exits.
-func (c *Client) StartLoadBalancer(clusterName string, background bool) error {
-	clusterDirPath := filepath.Join(config.CliConfig.Directory, "clusters", clusterName, ".cloud-provider-kind")
+// running after the CLI exits. Cancelling ctx stops a foreground process
+// but not a background one.
+func (c *Client) StartLoadBalancer(ctx context.Context, clusterName string, background bool) error {
+	clusterDirPath := c.loadBalancerDir(clusterName)
 
-	if err := ensureCloudProviderKindInstalled(); err != nil {
+	if err := ensureCloudProviderKindInstalled(ctx); err != nil {
 		return err
 	}
 
@@ -126,7 +188,7 @@ func (c *Client) StartLoadBalancer(clusterName string, background bool) error {
 	if !background {
 		if needSudo {
 			// run interactively so user can enter their sudo password
-			cmd := exec.Command("sudo", append([]string{"cloud-provider-kind"}, args...)...)
+			cmd := command(ctx, "sudo", append([]string{"cloud-provider-kind"}, args...)...)
 			cmd.Stdout = os.Stdout
 			cmd.Stderr = os.Stderr
 			cmd.Stdin = os.Stdin
@@ -135,7 +197,7 @@ func (c *Client) StartLoadBalancer(clusterName string, background bool) error {
 			}
 			return nil
 		}
-		out, err := runCmd("cloud-provider-kind", args...)
+		out, err := runCmd(ctx, "cloud-provider-kind", args...)
 		if err != nil {
 			return fmt.Errorf("cloud-provider-kind failed: %w; output: %s", err, out)
 		}
@@ -144,7 +206,7 @@ func (c *Client) StartLoadBalancer(clusterName string, background bool) error {
 
 	// background: if sudo is required, first validate sudo credentials interactively
 	if needSudo {
-		vcmd := exec.Command("sudo", "-v")
+		vcmd := command(ctx, "sudo", "-v")
 		vcmd.Stdout = os.Stdout
 		vcmd.Stderr = os.Stderr
 		vcmd.Stdin = os.Stdin
@@ -163,6 +225,7 @@ func (c *Client) StartLoadBalancer(clusterName string, background bool) error {
 		return fmt.Errorf("failed to open log file: %w", err)
 	}
 
+	// not bound to ctx: the process outlives the CLI
 	var cmd *exec.Cmd
 	if needSudo {
 		cmd = exec.Command("sudo", append([]string{"cloud-provider-kind"}, args...)...)
@@ -200,8 +263,8 @@ func (c *Client) StartLoadBalancer(clusterName string, background bool) error {
 // StopLoadBalancer stops a previously-started background cloud-provider-kind
 // process by reading the pid file, attempting to kill the process (using
 // sudo if necessary), and removing the `.cloud-provider-kind` directory.
-func (c *Client) StopLoadBalancer(clusterName string) error {
-	clusterDirPath := filepath.Join(config.CliConfig.Directory, "clusters", clusterName, ".cloud-provider-kind")
+func (c *Client) StopLoadBalancer(ctx context.Context, clusterName string) error {
+	clusterDirPath := c.loadBalancerDir(clusterName)
 	pidPath := filepath.Join(clusterDirPath, ".pid")
 
 	data, err := os.ReadFile(pidPath)
@@ -232,10 +295,10 @@ func (c *Client) StopLoadBalancer(clusterName string) error {
 			log.Warn().Err(err).Int("pid", pid).Msg("failed to send SIGTERM to process; attempting alternatives")
 			// Try using sudo kill if available (process may be owned by root)
 			if isInstalled("sudo") {
-				out, e := runCmd("sudo", "kill", "-TERM", pidStr)
+				out, e := runCmd(ctx, "sudo", "kill", "-TERM", pidStr)
 				if e != nil {
 					log.Error().Err(e).Str("output", out).Msg("sudo kill -TERM failed; trying sudo kill -KILL")
-					out2, e2 := runCmd("sudo", "kill", "-KILL", pidStr)
+					out2, e2 := runCmd(ctx, "sudo", "kill", "-KILL", pidStr)
 					if e2 != nil {
 						log.Error().Err(e2).Str("output", out2).Msg("sudo kill -KILL failed")
 						return fmt.Errorf("failed to kill process %d: %w", pid, e2)
@@ -257,3 +320,13 @@ func (c *Client) StopLoadBalancer(clusterName string) error {
 	log.Info().Str("clusterDir", clusterDirPath).Msg("stopped cloud-provider-kind and removed directory")
 	return nil
 }
+
+// loadBalancerDir returns where the cloud-provider-kind state of the cluster
+// is kept.
+func (c *Client) loadBalancerDir(clusterName string) string {
+	dir := c.Directory
+	if dir == "" {
+		dir = config.CliConfig.Directory
+	}
+	return filepath.Join(dir, "clusters", clusterName, ".cloud-provider-kind")
+}
@@ -0,0 +1,185 @@
+package kubeconfig
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ErrNotMerged is returned by Client.Use when the context of the cluster
+// isn't in the kubeconfig.
+var ErrNotMerged = errors.New("cluster not merged into the kubeconfig")
+
+// ContextName returns the name of the context, cluster and user of a
+// localplane cluster, `localplane-<cluster>`.
+func ContextName(cluster string) string {
+	return "localplane-" + cluster
+}
+
+// Load reads the kubeconfig kind wrote for cluster and returns its current
+// context, with its cluster and user, all named ContextName(cluster).
+func Load(path, cluster string) (*api.Config, error) {
+	cfg, err := clientcmd.LoadFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	current := cfg.CurrentContext
+	if current == "" && len(cfg.Contexts) == 1 {
+		for name := range cfg.Contexts {
+			current = name
+		}
+	}
+	kctx, ok := cfg.Contexts[current]
+	if !ok {
+		return nil, fmt.Errorf("%s: no current context", path)
+	}
+	kcluster, ok := cfg.Clusters[kctx.Cluster]
+	if !ok {
+		return nil, fmt.Errorf("%s: context %s has no cluster %q", path, current, kctx.Cluster)
+	}
+	user, ok := cfg.AuthInfos[kctx.AuthInfo]
+	if !ok {
+		return nil, fmt.Errorf("%s: context %s has no user %q", path, current, kctx.AuthInfo)
+	}
+
+	name := ContextName(cluster)
+	out := api.NewConfig()
+	// the entries no longer come from path
+	kcluster, user, kctx = kcluster.DeepCopy(), user.DeepCopy(), kctx.DeepCopy()
+	kcluster.LocationOfOrigin, user.LocationOfOrigin, kctx.LocationOfOrigin = "", "", ""
+	kctx.Cluster, kctx.AuthInfo = name, name
+	out.Clusters[name] = kcluster
+	out.AuthInfos[name] = user
+	out.Contexts[name] = kctx
+	out.CurrentContext = name
+	return out, nil
+}
+
+// Normalize rewrites the kubeconfig kind wrote for cluster at path with the
+// names of Load.
+func Normalize(path, cluster string) error {
+	cfg, err := Load(path, cluster)
+	if err != nil {
+		return err
+	}
+	return clientcmd.WriteToFile(*cfg, path)
+}
+
+// Client edits the kubeconfig of the user the way kubectl does: Path when
+// set, else the files of $KUBECONFIG, else ~/.kube/config.
+type Client struct {
+	Path string
+}
+
+// NewClient creates a Client. Pass empty string for defaults.
+func NewClient(path string) *Client {
+	return &Client{Path: path}
+}
+
+// Merge adds the entries of cfg, as returned by Load, to the kubeconfig,
+// replacing those of the same name, and makes its context the current one
+// when use is set.
+func (c *Client) Merge(cfg *api.Config, use bool) error {
+	access, start, err := c.start()
+	if err != nil {
+		return err
+	}
+	for name, cluster := range cfg.Clusters {
+		cluster = cluster.DeepCopy()
+		// replaced entries stay in the file they come from
+		if old, ok := start.Clusters[name]; ok {
+			cluster.LocationOfOrigin = old.LocationOfOrigin
+		}
+		start.Clusters[name] = cluster
+	}
+	for name, user := range cfg.AuthInfos {
+		user = user.DeepCopy()
+		if old, ok := start.AuthInfos[name]; ok {
+			user.LocationOfOrigin = old.LocationOfOrigin
+		}
+		start.AuthInfos[name] = user
+	}
+	for name, kctx := range cfg.Contexts {
+		kctx = kctx.DeepCopy()
+		if old, ok := start.Contexts[name]; ok {
+			kctx.LocationOfOrigin = old.LocationOfOrigin
+		}
+		start.Contexts[name] = kctx
+	}
+	if use {
+		start.CurrentContext = cfg.CurrentContext
+	}
+	return clientcmd.ModifyConfig(access, *start, false)
+}
+
+// Unmerge removes the context, cluster and user of cluster from the
+// kubeconfig, and the current context when it is that one. It reports
+// whether there was something to remove.
+func (c *Client) Unmerge(cluster string) (bool, error) {
+	access, start, err := c.start()
+	if err != nil {
+		return false, err
+	}
+	name := ContextName(cluster)
+	_, hasContext := start.Contexts[name]
+	_, hasCluster := start.Clusters[name]
+	_, hasUser := start.AuthInfos[name]
+	if !hasContext && !hasCluster && !hasUser {
+		return false, nil
+	}
+	delete(start.Contexts, name)
+	delete(start.Clusters, name)
+	delete(start.AuthInfos, name)
+	if start.CurrentContext == name {
+		start.CurrentContext = ""
+	}
+	return true, clientcmd.ModifyConfig(access, *start, false)
+}
+
+// Use makes the context of cluster the current one. It returns ErrNotMerged
+// when the kubeconfig doesn't have it.
+func (c *Client) Use(cluster string) error {
+	access, start, err := c.start()
+	if err != nil {
+		return err
+	}
+	name := ContextName(cluster)
+	if _, ok := start.Contexts[name]; !ok {
+		return fmt.Errorf("%s: %w", name, ErrNotMerged)
+	}
+	start.CurrentContext = name
+	return clientcmd.ModifyConfig(access, *start, false)
+}
+
+// Filename returns the file that gets the new entries of the kubeconfig.
+func (c *Client) Filename() string {
+	return c.pathOptions().GetDefaultFilename()
+}
+
+// start returns the kubeconfig files of c and their merged content.
+func (c *Client) start() (clientcmd.ConfigAccess, *api.Config, error) {
+	access := c.pathOptions()
+	if c.Path != "" {
+		// an explicit file must exist to be loaded
+		if _, err := os.Stat(c.Path); errors.Is(err, os.ErrNotExist) {
+			if err := clientcmd.WriteToFile(*api.NewConfig(), c.Path); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	start, err := access.GetStartingConfig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading the kubeconfig: %w", err)
+	}
+	return access, start, nil
+}
+
+// pathOptions returns the kubeconfig files of c.
+func (c *Client) pathOptions() *clientcmd.PathOptions {
+	options := clientcmd.NewDefaultPathOptions()
+	options.LoadingRules.ExplicitPath = c.Path
+	return options
+}
@@ -1,6 +1,7 @@
 package kubectl
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -9,6 +10,8 @@ import (
 	"path/filepath"
 	"strings"
 
+	"localplane/utils/retry"
+
 	"github.com/rs/zerolog/log"
 )
 
@@ -20,6 +23,9 @@ type Client struct {
 	Kubeconfig *string
 	// Extra args to pass to kubectl (e.g., --namespace)
 	ExtraArgs []string
+	// Retry is the backoff of the commands failing because the API server
+	// is unreachable or overloaded.
+	Retry retry.Policy
 }
 
 // NewClient creates a basic Client.
@@ -54,6 +60,65 @@ func (c *Client) buildBaseArgs() []string {
 	return args
 }
 
+// transientErrors are the kubectl outputs of API server failures worth
+// retrying.
+var transientErrors = []string{
+	"connection refused",
+	"connection reset",
+	"i/o timeout",
+	"TLS handshake timeout",
+	"EOF",
+	"the server is currently unable to handle the request",
+	"ServiceUnavailable",
+	"Internal error occurred",
+	"etcdserver",
+	"Too many requests",
+}
+
+// isTransient reports whether the kubectl output shows a failure worth
+// retrying.
+func isTransient(output string) bool {
+	for _, e := range transientErrors {
+		if strings.Contains(output, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// run runs kubectl with args and the base args, feeding it stdin when not
+// nil, and returns its stdout. Transient failures are retried with the Retry
+// policy; the returned error includes the kubectl stderr.
+func (c *Client) run(ctx context.Context, stdin []byte, args ...string) ([]byte, error) {
+	kubectlPath, err := c.resolveKubectl()
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, c.buildBaseArgs()...)
+	log.Debug().Str("cmd", "kubectl").Str("args", strings.Join(args, " ")).Msg("running command")
+
+	var out []byte
+	err = retry.Do(ctx, c.Retry, func() error {
+		cmd := exec.CommandContext(ctx, kubectlPath, args...)
+		if stdin != nil {
+			cmd.Stdin = bytes.NewReader(stdin)
+		}
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		var err error
+		if out, err = cmd.Output(); err == nil {
+			return nil
+		}
+		output := strings.TrimSpace(stderr.String())
+		err = fmt.Errorf("%w; output: %s", err, output)
+		if !isTransient(output) {
+			return retry.Permanent(err)
+		}
+		return err
+	})
+	return out, err
+}
+
 // ApplyPaths takes a list of glob patterns, expands them on the local filesystem,
 // and runs `kubectl apply -f` with the matched files and directories. Patterns
 // that don't match anything cause an error.
@@ -99,28 +164,72 @@ func (c *Client) ApplyPaths(ctx context.Context, patterns []string) error {
 		return fmt.Errorf("no files to apply")
 	}
 
-	kubectlPath, err := c.resolveKubectl()
-	if err != nil {
-		return err
-	}
-
 	// Build command: kubectl apply -f <item1> -f <item2> ... [--kubeconfig ...] [extra args]
 	args := []string{"apply"}
 	for _, m := range matches {
 		args = append(args, "-f", m)
 	}
-	args = append(args, c.buildBaseArgs()...)
+	out, err := c.run(ctx, nil, args...)
+	if err != nil {
+		return fmt.Errorf("kubectl apply failed: %w", err)
+	}
+	log.Debug().Str("output", strings.TrimSpace(string(out))).Msg("kubectl apply completed")
+	return nil
+}
 
-	cmd := exec.CommandContext(ctx, kubectlPath, args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+// ApplyManifest pipes the provided YAML/JSON manifest to `kubectl apply -f -`.
+func (c *Client) ApplyManifest(ctx context.Context, manifest []byte) error {
+	if len(manifest) == 0 {
+		return fmt.Errorf("no manifest provided")
+	}
 
-	if err := cmd.Run(); err != nil {
+	out, err := c.run(ctx, manifest, "apply", "-f", "-")
+	if err != nil {
 		return fmt.Errorf("kubectl apply failed: %w", err)
 	}
+	log.Debug().Str("output", strings.TrimSpace(string(out))).Msg("kubectl apply completed")
+	return nil
+}
+
+// Patch runs `kubectl patch <kind> <name> -n <namespace> --type merge -p <patch>`.
+func (c *Client) Patch(ctx context.Context, kind, name, namespace, patch string) error {
+	if kind == "" || name == "" || namespace == "" {
+		return fmt.Errorf("kind, name and namespace must be provided")
+	}
+
+	if _, err := c.run(ctx, nil, "patch", kind, name, "-n", namespace, "--type", "merge", "-p", patch); err != nil {
+		return fmt.Errorf("kubectl patch failed: %w", err)
+	}
+	return nil
+}
+
+// Annotate sets annotation key to value on the named object, overwriting
+// any previous value (`kubectl annotate --overwrite`).
+func (c *Client) Annotate(ctx context.Context, kind, name, namespace, key, value string) error {
+	if kind == "" || name == "" || namespace == "" || key == "" {
+		return fmt.Errorf("kind, name, namespace and key must be provided")
+	}
+
+	if _, err := c.run(ctx, nil, "annotate", kind, name, "-n", namespace, "--overwrite", key+"="+value); err != nil {
+		return fmt.Errorf("kubectl annotate failed: %w", err)
+	}
 	return nil
 }
 
+// GetJSON runs `kubectl get <kind> -n <namespace> -o json` and returns the
+// raw List document, for resources without a dedicated helper.
+func (c *Client) GetJSON(ctx context.Context, kind, namespace string) ([]byte, error) {
+	if kind == "" || namespace == "" {
+		return nil, fmt.Errorf("kind and namespace must be provided")
+	}
+
+	out, err := c.run(ctx, nil, "get", kind, "-n", namespace, "-o", "json")
+	if err != nil {
+		return nil, fmt.Errorf("kubectl get %s failed: %w", kind, err)
+	}
+	return out, nil
+}
+
 // ServicePort describes a service port.
 type ServicePort struct {
 	Name     string
@@ -136,27 +245,38 @@ type Service struct {
 	Type        string
 	ClusterIP   string
 	ExternalIPs []string
-	Ports       []ServicePort
+	// ExternalHostnames holds status.loadBalancer.ingress[].hostname entries,
+	// used by load balancers that publish a DNS name instead of an IP.
+	ExternalHostnames []string
+	Ports             []ServicePort
+}
+
+// ExternalAddress returns the first external IP of the service, falling back
+// to the first external hostname. Empty when none has been assigned yet.
+func (s Service) ExternalAddress() string {
+	if len(s.ExternalIPs) > 0 {
+		return s.ExternalIPs[0]
+	}
+	if len(s.ExternalHostnames) > 0 {
+		return s.ExternalHostnames[0]
+	}
+	return ""
 }
 
 // ListServices returns services in the given namespace. If svcType is
 // non-nil and non-empty, results are filtered to services whose
-// spec.type matches (case-insensitive) the provided value.
-func (c *Client) ListServices(ctx context.Context, namespace string, svcType *string) ([]Service, error) {
+// spec.type matches (case-insensitive) the provided value. A non-empty
+// selector is passed to kubectl as a label selector (`-l`).
+func (c *Client) ListServices(ctx context.Context, namespace string, svcType *string, selector string) ([]Service, error) {
 	if namespace == "" {
 		return nil, fmt.Errorf("namespace must be provided")
 	}
 
-	kubectlPath, err := c.resolveKubectl()
-	if err != nil {
-		return nil, err
-	}
-
 	args := []string{"get", "svc", "-n", namespace, "-o", "json"}
-	args = append(args, c.buildBaseArgs()...)
-
-	cmd := exec.CommandContext(ctx, kubectlPath, args...)
-	out, err := cmd.Output()
+	if selector != "" {
+		args = append(args, "-l", selector)
+	}
+	out, err := c.run(ctx, nil, args...)
 	if err != nil {
 		return nil, fmt.Errorf("kubectl get services failed: %w", err)
 	}
@@ -181,7 +301,8 @@ func (c *Client) ListServices(ctx context.Context, namespace string, svcType *st
 			Status struct {
 				LoadBalancer struct {
 					Ingress []struct {
-						IP string `json:"ip"`
+						IP       string `json:"ip"`
+						Hostname string `json:"hostname"`
 					} `json:"ingress"`
 				} `json:"loadBalancer"`
 			} `json:"status"`
@@ -207,11 +328,15 @@ func (c *Client) ListServices(ctx context.Context, namespace string, svcType *st
 		if len(it.Spec.ExternalIPs) > 0 {
 			s.ExternalIPs = append(s.ExternalIPs, it.Spec.ExternalIPs...)
 		}
-		// include any loadBalancer ingress IPs (status.loadBalancer.ingress[].ip)
+		// include any loadBalancer ingress IPs and hostnames
+		// (status.loadBalancer.ingress[].ip / .hostname)
 		for _, ing := range it.Status.LoadBalancer.Ingress {
 			if ing.IP != "" {
 				s.ExternalIPs = append(s.ExternalIPs, ing.IP)
 			}
+			if ing.Hostname != "" {
+				s.ExternalHostnames = append(s.ExternalHostnames, ing.Hostname)
+			}
 		}
 		for _, p := range it.Spec.Ports {
 			s.Ports = append(s.Ports, ServicePort{Name: p.Name, Port: p.Port, Protocol: p.Protocol})
@@ -229,21 +354,11 @@ func (c *Client) CreateToken(ctx context.Context, saName, namespace string) (str
 		return "", fmt.Errorf("service account name and namespace must be provided")
 	}
 
-	kubectlPath, err := c.resolveKubectl()
-	if err != nil {
-		return "", err
-	}
-
-	args := []string{"create", "token", saName, "-n", namespace}
-	args = append(args, c.buildBaseArgs()...)
-
-	cmd := exec.CommandContext(ctx, kubectlPath, args...)
-	out, err := cmd.Output()
+	out, err := c.run(ctx, nil, "create", "token", saName, "-n", namespace)
 	if err != nil {
 		return "", fmt.Errorf("kubectl create token failed: %w", err)
 	}
-
-	log.Info().Str("cmd", strings.Join(cmd.Args, " ")).Msg("kubectl command executed")
+	log.Info().Str("serviceaccount", saName).Str("namespace", namespace).Msg("kubectl token created")
 
 	token := strings.TrimSpace(string(out))
 	return token, nil
@@ -0,0 +1,230 @@
+package merge
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Conflict markers written around the two sides of a conflicting hunk, like
+// `git merge` does.
+const (
+	LocalMarker     = "<<<<<<< local"
+	SeparatorMarker = "======="
+	UpstreamMarker  = ">>>>>>> upstream"
+)
+
+// Tree is the content of a directory: file contents by slash separated path.
+type Tree map[string][]byte
+
+// Text merges the changes made to base by local and by upstream, line by
+// line (diff3). Hunks changed on both sides differently are written with
+// conflict markers, and conflict is then true.
+func Text(base, local, upstream string) (merged string, conflict bool) {
+	b, l, u := splitLines(base), splitLines(local), splitLines(upstream)
+	ml, mu := match(b, l), match(b, u)
+
+	var out strings.Builder
+	i, x, y := 0, 0, 0
+	for i < len(b) || x < len(l) || y < len(u) {
+		// stable run: lines unchanged on both sides
+		n := 0
+		for i+n < len(b) && ml[i+n] == x+n && mu[i+n] == y+n {
+			n++
+		}
+		if n > 0 {
+			for _, line := range b[i : i+n] {
+				out.WriteString(line)
+			}
+			i, x, y = i+n, x+n, y+n
+			continue
+		}
+
+		// unstable hunk: up to the next base line kept by both sides
+		j := i
+		for j < len(b) && (ml[j] < 0 || mu[j] < 0) {
+			j++
+		}
+		xe, ye := len(l), len(u)
+		if j < len(b) {
+			xe, ye = ml[j], mu[j]
+		}
+		hb, hl, hu := b[i:j], l[x:xe], u[y:ye]
+		switch {
+		case equal(hb, hl):
+			writeLines(&out, hu)
+		case equal(hb, hu) || equal(hl, hu):
+			writeLines(&out, hl)
+		default:
+			conflict = true
+			out.WriteString(LocalMarker + "\n")
+			writeLines(&out, terminate(hl))
+			out.WriteString(SeparatorMarker + "\n")
+			writeLines(&out, terminate(hu))
+			out.WriteString(UpstreamMarker + "\n")
+		}
+		i, x, y = j, xe, ye
+	}
+	return out.String(), conflict
+}
+
+// Trees merges the files changed in local and in upstream since base. The
+// result holds the files to keep; a path missing from it is deleted. Files
+// whose changes can't be merged are listed in conflicts: text files get
+// conflict markers. When a side deleted the file, the other side's version
+// is kept; when both rewrote a binary file, the local version is kept.
+func Trees(base, local, upstream Tree) (result Tree, conflicts []string) {
+	paths := map[string]struct{}{}
+	for _, t := range []Tree{base, local, upstream} {
+		for p := range t {
+			paths[p] = struct{}{}
+		}
+	}
+
+	result = Tree{}
+	for p := range paths {
+		b, inB := base[p]
+		l, inL := local[p]
+		u, inU := upstream[p]
+		same := func(c1 []byte, in1 bool, c2 []byte, in2 bool) bool {
+			return in1 == in2 && bytes.Equal(c1, c2)
+		}
+		switch {
+		case same(l, inL, u, inU) || same(b, inB, u, inU):
+			if inL {
+				result[p] = l
+			}
+		case same(b, inB, l, inL):
+			if inU {
+				result[p] = u
+			}
+		case inL && inU && !isBinary(b) && !isBinary(l) && !isBinary(u):
+			merged, conflict := Text(string(b), string(l), string(u))
+			result[p] = []byte(merged)
+			if conflict {
+				conflicts = append(conflicts, p)
+			}
+		default:
+			if inL {
+				result[p] = l
+			} else {
+				result[p] = u
+			}
+			conflicts = append(conflicts, p)
+		}
+	}
+	sort.Strings(conflicts)
+	return result, conflicts
+}
+
+// splitLines splits s after each newline; the last line may have none.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// match returns, for each line of a, the index of the matching line of b in
+// a longest common subsequence of the two, or -1.
+func match(a, b []string) []int {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	m := make([]int, len(a))
+	i, j := 0, 0
+	for i < len(a) {
+		switch {
+		case j < len(b) && a[i] == b[j]:
+			m[i] = j
+			i, j = i+1, j+1
+		case j < len(b) && lcs[i][j+1] >= lcs[i+1][j]:
+			j++
+		default:
+			m[i] = -1
+			i++
+		}
+	}
+	return m
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// terminate makes sure the last line ends with a newline, so a conflict
+// marker never ends up on the same line.
+func terminate(lines []string) []string {
+	if n := len(lines); n > 0 && !strings.HasSuffix(lines[n-1], "\n") {
+		lines = append(lines[:n-1:n-1], lines[n-1]+"\n")
+	}
+	return lines
+}
+
+func writeLines(out *strings.Builder, lines []string) {
+	for _, line := range lines {
+		out.WriteString(line)
+	}
+}
+
+func isBinary(data []byte) bool {
+	return bytes.IndexByte(data, 0) >= 0
+}
+
+// ReadTree reads the regular files under dir, skipping .git directories. A
+// missing dir is an empty tree.
+func ReadTree(dir string) (Tree, error) {
+	tree := Tree{}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && p == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		tree[filepath.ToSlash(rel)] = data
+		return nil
+	})
+	return tree, err
+}
@@ -0,0 +1,268 @@
+package merge
+
+import (
+	"bytes"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name                  string
+		base, local, upstream string
+		want                  string
+		conflict              bool
+	}{
+		{
+			name:     "unchanged",
+			base:     "a\nb\n",
+			local:    "a\nb\n",
+			upstream: "a\nb\n",
+			want:     "a\nb\n",
+		},
+		{
+			name:     "local change only",
+			base:     "a\nb\nc\n",
+			local:    "a\nB\nc\n",
+			upstream: "a\nb\nc\n",
+			want:     "a\nB\nc\n",
+		},
+		{
+			name:     "upstream change only",
+			base:     "a\nb\nc\n",
+			local:    "a\nb\nc\n",
+			upstream: "a\nB\nc\n",
+			want:     "a\nB\nc\n",
+		},
+		{
+			name:     "changes on both sides in different hunks",
+			base:     "a\nb\nc\nd\ne\n",
+			local:    "a\nB\nc\nd\ne\n",
+			upstream: "a\nb\nc\nD\ne\n",
+			want:     "a\nB\nc\nD\ne\n",
+		},
+		{
+			name:     "same change on both sides",
+			base:     "a\nb\nc\n",
+			local:    "a\nB\nc\n",
+			upstream: "a\nB\nc\n",
+			want:     "a\nB\nc\n",
+		},
+		{
+			name:     "conflicting hunks",
+			base:     "a\nb\nc\n",
+			local:    "a\nL\nc\n",
+			upstream: "a\nU\nc\n",
+			want:     "a\n" + LocalMarker + "\nL\n" + SeparatorMarker + "\nU\n" + UpstreamMarker + "\nc\n",
+			conflict: true,
+		},
+		{
+			name:     "delete on one side, change elsewhere on the other",
+			base:     "a\nb\nc\nd\ne\n",
+			local:    "a\nc\nd\ne\n",
+			upstream: "a\nb\nc\nD\ne\n",
+			want:     "a\nc\nD\ne\n",
+		},
+		{
+			name:     "delete on one side, change of the same line on the other",
+			base:     "a\nb\nc\n",
+			local:    "a\nc\n",
+			upstream: "a\nB\nc\n",
+			want:     "a\n" + LocalMarker + "\n" + SeparatorMarker + "\nB\n" + UpstreamMarker + "\nc\n",
+			conflict: true,
+		},
+		{
+			name:     "insertion at end of file",
+			base:     "a\nb\n",
+			local:    "a\nb\n",
+			upstream: "a\nb\nc\n",
+			want:     "a\nb\nc\n",
+		},
+		{
+			name:     "same insertion at end of file on both sides",
+			base:     "a\n",
+			local:    "a\nb\n",
+			upstream: "a\nb\n",
+			want:     "a\nb\n",
+		},
+		{
+			name:     "conflicting insertions at end of file",
+			base:     "a\n",
+			local:    "a\nl\n",
+			upstream: "a\nu\n",
+			want:     "a\n" + LocalMarker + "\nl\n" + SeparatorMarker + "\nu\n" + UpstreamMarker + "\n",
+			conflict: true,
+		},
+		{
+			name:     "no trailing newline, change on one side",
+			base:     "a\nb",
+			local:    "a\nb",
+			upstream: "A\nb",
+			want:     "A\nb",
+		},
+		{
+			name:     "no trailing newline, append on one side",
+			base:     "a\nb",
+			local:    "a\nb\nc",
+			upstream: "a\nb",
+			want:     "a\nb\nc",
+		},
+		{
+			name:     "no trailing newline, conflict",
+			base:     "a\nb",
+			local:    "a\nl",
+			upstream: "a\nu",
+			want:     "a\n" + LocalMarker + "\nl\n" + SeparatorMarker + "\nu\n" + UpstreamMarker + "\n",
+			conflict: true,
+		},
+		{
+			name:     "empty base, added on one side",
+			base:     "",
+			local:    "",
+			upstream: "a\nb\n",
+			want:     "a\nb\n",
+		},
+		{
+			name:     "empty base, same content on both sides",
+			base:     "",
+			local:    "a\n",
+			upstream: "a\n",
+			want:     "a\n",
+		},
+		{
+			name:     "empty base, different content on both sides",
+			base:     "",
+			local:    "l\n",
+			upstream: "u\n",
+			want:     LocalMarker + "\nl\n" + SeparatorMarker + "\nu\n" + UpstreamMarker + "\n",
+			conflict: true,
+		},
+		{
+			name:     "everything deleted on one side",
+			base:     "a\nb\n",
+			local:    "",
+			upstream: "a\nb\n",
+			want:     "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflict := Text(tt.base, tt.local, tt.upstream)
+			if got != tt.want || conflict != tt.conflict {
+				t.Errorf("Text() = %q, %v; want %q, %v", got, conflict, tt.want, tt.conflict)
+			}
+		})
+	}
+}
+
+func TestTrees(t *testing.T) {
+	bin := func(s string) []byte { return append([]byte{0}, s...) }
+	tests := []struct {
+		name                  string
+		base, local, upstream Tree
+		want                  Tree
+		conflicts             []string
+	}{
+		{
+			name:     "local change only",
+			base:     Tree{"a.yaml": []byte("a\n")},
+			local:    Tree{"a.yaml": []byte("A\n")},
+			upstream: Tree{"a.yaml": []byte("a\n")},
+			want:     Tree{"a.yaml": []byte("A\n")},
+		},
+		{
+			name:     "upstream adds a file",
+			base:     Tree{"a.yaml": []byte("a\n")},
+			local:    Tree{"a.yaml": []byte("a\n")},
+			upstream: Tree{"a.yaml": []byte("a\n"), "b.yaml": []byte("b\n")},
+			want:     Tree{"a.yaml": []byte("a\n"), "b.yaml": []byte("b\n")},
+		},
+		{
+			name:     "upstream deletes an unchanged file",
+			base:     Tree{"a.yaml": []byte("a\n"), "b.yaml": []byte("b\n")},
+			local:    Tree{"a.yaml": []byte("a\n"), "b.yaml": []byte("b\n")},
+			upstream: Tree{"a.yaml": []byte("a\n")},
+			want:     Tree{"a.yaml": []byte("a\n")},
+		},
+		{
+			name:     "same change on both sides",
+			base:     Tree{"a.yaml": []byte("a\n")},
+			local:    Tree{"a.yaml": []byte("A\n")},
+			upstream: Tree{"a.yaml": []byte("A\n")},
+			want:     Tree{"a.yaml": []byte("A\n")},
+		},
+		{
+			name:     "text changes merged line by line",
+			base:     Tree{"a.yaml": []byte("a\nb\nc\n")},
+			local:    Tree{"a.yaml": []byte("A\nb\nc\n")},
+			upstream: Tree{"a.yaml": []byte("a\nb\nC\n")},
+			want:     Tree{"a.yaml": []byte("A\nb\nC\n")},
+		},
+		{
+			name:      "conflicting text changes",
+			base:      Tree{"a.yaml": []byte("a\n")},
+			local:     Tree{"a.yaml": []byte("l\n")},
+			upstream:  Tree{"a.yaml": []byte("u\n")},
+			want:      Tree{"a.yaml": []byte(LocalMarker + "\nl\n" + SeparatorMarker + "\nu\n" + UpstreamMarker + "\n")},
+			conflicts: []string{"a.yaml"},
+		},
+		{
+			name:      "deleted locally, modified upstream",
+			base:      Tree{"a.yaml": []byte("a\n")},
+			local:     Tree{},
+			upstream:  Tree{"a.yaml": []byte("u\n")},
+			want:      Tree{"a.yaml": []byte("u\n")},
+			conflicts: []string{"a.yaml"},
+		},
+		{
+			name:      "modified locally, deleted upstream",
+			base:      Tree{"a.yaml": []byte("a\n")},
+			local:     Tree{"a.yaml": []byte("l\n")},
+			upstream:  Tree{},
+			want:      Tree{"a.yaml": []byte("l\n")},
+			conflicts: []string{"a.yaml"},
+		},
+		{
+			name:     "binary changed on one side",
+			base:     Tree{"logo.png": bin("a")},
+			local:    Tree{"logo.png": bin("a")},
+			upstream: Tree{"logo.png": bin("u")},
+			want:     Tree{"logo.png": bin("u")},
+		},
+		{
+			name:      "binary changed on both sides",
+			base:      Tree{"logo.png": bin("a")},
+			local:     Tree{"logo.png": bin("l")},
+			upstream:  Tree{"logo.png": bin("u")},
+			want:      Tree{"logo.png": bin("l")},
+			conflicts: []string{"logo.png"},
+		},
+		{
+			name:     "empty base, same file added on both sides",
+			base:     Tree{},
+			local:    Tree{"a.yaml": []byte("a\n")},
+			upstream: Tree{"a.yaml": []byte("a\n")},
+			want:     Tree{"a.yaml": []byte("a\n")},
+		},
+		{
+			name:      "empty base, different files added on both sides",
+			base:      nil,
+			local:     Tree{"a.yaml": []byte("l\n"), "b.yaml": []byte("b\n")},
+			upstream:  Tree{"a.yaml": []byte("u\n"), "c.yaml": []byte("c\n")},
+			want:      Tree{"a.yaml": []byte(LocalMarker + "\nl\n" + SeparatorMarker + "\nu\n" + UpstreamMarker + "\n"), "b.yaml": []byte("b\n"), "c.yaml": []byte("c\n")},
+			conflicts: []string{"a.yaml"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts := Trees(tt.base, tt.local, tt.upstream)
+			if !maps.EqualFunc(got, tt.want, bytes.Equal) {
+				t.Errorf("Trees() result = %q; want %q", got, tt.want)
+			}
+			if !slices.Equal(conflicts, tt.conflicts) {
+				t.Errorf("Trees() conflicts = %v; want %v", conflicts, tt.conflicts)
+			}
+		})
+	}
+}
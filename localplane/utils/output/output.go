@@ -0,0 +1,88 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"localplane/utils/clierror"
+
+	"github.com/spf13/cobra"
+)
+
+// Output formats of the --output flag.
+const (
+	Text = "text"
+	JSON = "json"
+)
+
+// Step statuses.
+const (
+	StepOK      = "ok"
+	StepWarning = "warning"
+	StepFailed  = "failed"
+	StepSkipped = "skipped"
+)
+
+// AddFlag adds the --output (-o) flag to cmd.
+func AddFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP("output", "o", Text, fmt.Sprintf("output format (%s, %s); json prints a single result object on stdout and never prompts", Text, JSON))
+}
+
+// Format returns the validated --output format of cmd.
+func Format(cmd *cobra.Command) (string, error) {
+	format, _ := cmd.Flags().GetString("output")
+	switch format {
+	case "", Text:
+		return Text, nil
+	case JSON:
+		return JSON, nil
+	default:
+		return "", clierror.Newf(clierror.ExitUsage, "unknown output format %q (expected %s or %s)", format, Text, JSON)
+	}
+}
+
+// Step is one step of a command with its outcome.
+type Step struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	DurationMs int64  `json:"durationMs"`
+	Error      string `json:"error,omitempty"`
+}
+
+// NewStep returns the step name started at start, failed (or with a warning)
+// when err is set.
+func NewStep(name string, start time.Time, err error, warnOnly bool) Step {
+	step := Step{Name: name, Status: StepOK, DurationMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		step.Status = StepFailed
+		if warnOnly {
+			step.Status = StepWarning
+		}
+		step.Error = err.Error()
+	}
+	return step
+}
+
+// ErrorInfo describes the error a command failed with.
+type ErrorInfo struct {
+	Class    string `json:"class"`
+	ExitCode int    `json:"exitCode"`
+	Message  string `json:"message"`
+}
+
+// NewErrorInfo describes err, nil when err is nil.
+func NewErrorInfo(err error) *ErrorInfo {
+	if err == nil {
+		return nil
+	}
+	return &ErrorInfo{Class: clierror.Class(err), ExitCode: clierror.Code(err), Message: err.Error()}
+}
+
+// PrintJSON writes v as indented JSON.
+func PrintJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
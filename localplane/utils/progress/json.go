@@ -0,0 +1,69 @@
+package progress
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"localplane/utils/output"
+)
+
+// Event is a progress event written by the JSON reporter, one per line.
+type Event struct {
+	Time       time.Time `json:"time"`
+	Event      string    `json:"event"`
+	Step       string    `json:"step"`
+	Label      string    `json:"label,omitempty"`
+	Status     string    `json:"status"`
+	DurationMs int64     `json:"durationMs,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// StatusRunning is the status of the event of a started step.
+const StatusRunning = "running"
+
+// jsonReporter writes step events as JSON lines. Logs are written as the
+// JSON lines of zerolog, so every line of its output is a JSON object.
+type jsonReporter struct {
+	mu  sync.Mutex
+	w   io.Writer
+	enc *json.Encoder
+}
+
+func newJSONReporter(w io.Writer) *jsonReporter {
+	return &jsonReporter{w: w, enc: json.NewEncoder(w)}
+}
+
+func (r *jsonReporter) write(ev Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ev.Time = time.Now()
+	ev.Event = "step"
+	_ = r.enc.Encode(ev)
+}
+
+func (r *jsonReporter) Start(name, label string) {
+	r.write(Event{Step: name, Label: label, Status: StatusRunning})
+}
+
+func (r *jsonReporter) Finish(step output.Step) {
+	r.write(Event{Step: step.Name, Status: step.Status, DurationMs: step.DurationMs, Error: step.Error})
+}
+
+func (r *jsonReporter) Skip(name, label string) {
+	r.write(Event{Step: name, Label: label, Status: output.StepSkipped})
+}
+
+func (r *jsonReporter) LogWriter() io.Writer {
+	return r
+}
+
+// Write writes a log line between events.
+func (r *jsonReporter) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.w.Write(p)
+}
+
+func (r *jsonReporter) Close() {}
@@ -0,0 +1,57 @@
+package progress
+
+import (
+	"io"
+	"time"
+
+	"localplane/utils/output"
+
+	"github.com/rs/zerolog"
+)
+
+// plainReporter logs one line when a step starts and one with its outcome,
+// for logs that are read after the fact (CI).
+type plainReporter struct {
+	w      io.Writer
+	logger zerolog.Logger
+	labels map[string]string
+}
+
+func newPlainReporter(w io.Writer, noColor bool) *plainReporter {
+	cw := zerolog.ConsoleWriter{Out: w, NoColor: noColor}
+	return &plainReporter{
+		w:      cw,
+		logger: zerolog.New(cw).With().Timestamp().Logger(),
+		labels: map[string]string{},
+	}
+}
+
+func (r *plainReporter) Start(name, label string) {
+	r.labels[name] = label
+	r.logger.Info().Str("step", name).Msg(label)
+}
+
+func (r *plainReporter) Finish(step output.Step) {
+	label := r.labels[step.Name]
+	if label == "" {
+		label = step.Name
+	}
+	ev := r.logger.Info()
+	switch step.Status {
+	case output.StepWarning:
+		ev = r.logger.Warn().Str("error", step.Error)
+	case output.StepFailed:
+		ev = r.logger.Error().Str("error", step.Error)
+	}
+	ev.Str("step", step.Name).Str("status", step.Status).Dur("elapsed", time.Duration(step.DurationMs)*time.Millisecond).Msg(label)
+}
+
+func (r *plainReporter) Skip(name, label string) {
+	r.logger.Info().Str("step", name).Str("status", output.StepSkipped).Msg(label)
+}
+
+func (r *plainReporter) LogWriter() io.Writer {
+	return r.w
+}
+
+func (r *plainReporter) Close() {}
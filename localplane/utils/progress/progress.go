@@ -0,0 +1,77 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"localplane/utils/output"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/term"
+)
+
+// Progress modes.
+const (
+	// ModeAuto renders with ModeTTY on a terminal, ModePlain otherwise.
+	ModeAuto = "auto"
+	// ModeTTY redraws a step list with status and elapsed time.
+	ModeTTY = "tty"
+	// ModePlain logs one line per step start and outcome, for CI.
+	ModePlain = "plain"
+	// ModeJSON writes one JSON event per line, logs included.
+	ModeJSON = "json"
+)
+
+// Reporter reports the progress of the steps of a long-running command.
+// Steps run one at a time.
+type Reporter interface {
+	// Start marks the step name as running, shown as label.
+	Start(name, label string)
+	// Finish reports the outcome of the running step.
+	Finish(step output.Step)
+	// Skip reports the step name as skipped.
+	Skip(name, label string)
+	// LogWriter returns where logs must be written while the reporter is in
+	// use, so they don't corrupt its output.
+	LogWriter() io.Writer
+	// Close stops rendering.
+	Close()
+}
+
+// New returns the Reporter for mode writing to f. An empty mode is
+// ModeAuto.
+func New(mode string, f *os.File) (Reporter, error) {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "", ModeAuto:
+		if term.IsTerminal(int(f.Fd())) {
+			return newTTYReporter(f), nil
+		}
+		return newPlainReporter(f, true), nil
+	case ModeTTY:
+		return newTTYReporter(f), nil
+	case ModePlain:
+		return newPlainReporter(f, !term.IsTerminal(int(f.Fd()))), nil
+	case ModeJSON:
+		return newJSONReporter(f), nil
+	default:
+		return nil, fmt.Errorf("unsupported progress mode %q (supported: %s)", mode, strings.Join(Modes(), ", "))
+	}
+}
+
+// Modes returns the supported progress modes.
+func Modes() []string {
+	return []string{ModeAuto, ModeTTY, ModePlain, ModeJSON}
+}
+
+// Use routes the global logger through r until the returned function is
+// called, which also closes r.
+func Use(r Reporter) func() {
+	prev := log.Logger
+	log.Logger = log.Output(r.LogWriter())
+	return func() {
+		r.Close()
+		log.Logger = prev
+	}
+}
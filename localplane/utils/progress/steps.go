@@ -0,0 +1,62 @@
+package progress
+
+import (
+	"context"
+	"time"
+
+	"localplane/utils/output"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Steps runs the steps of a command, reporting them to a Reporter and
+// recording their outcome for the command result.
+type Steps struct {
+	reporter Reporter
+	list     []output.Step
+}
+
+// NewSteps returns Steps reporting to r, or to nothing when r is nil.
+func NewSteps(r Reporter) *Steps {
+	return &Steps{reporter: r, list: []output.Step{}}
+}
+
+// Run runs fn as the step name, shown as label. Failures of warnOnly steps
+// are logged and recorded as warnings; other failures are returned. Once ctx
+// is done the step isn't run and its cause is returned, and failures are
+// returned even for warnOnly steps, so an interruption always stops the
+// command.
+func (s *Steps) Run(ctx context.Context, name, label string, warnOnly bool, fn func() error) error {
+	if ctx.Err() != nil {
+		return context.Cause(ctx)
+	}
+	if s.reporter != nil {
+		s.reporter.Start(name, label)
+	}
+	start := time.Now()
+	err := fn()
+	warnOnly = warnOnly && ctx.Err() == nil
+	step := output.NewStep(name, start, err, warnOnly)
+	if s.reporter != nil {
+		s.reporter.Finish(step)
+	}
+	s.list = append(s.list, step)
+	if err != nil && warnOnly {
+		log.Warn().Err(err).Str("step", name).Msg("step failed; continuing")
+		return nil
+	}
+	return err
+}
+
+// Skip records the step name as skipped.
+func (s *Steps) Skip(name, label string) {
+	if s.reporter != nil {
+		s.reporter.Skip(name, label)
+	}
+	s.list = append(s.list, output.Step{Name: name, Status: output.StepSkipped})
+}
+
+// List returns the steps run so far.
+func (s *Steps) List() []output.Step {
+	return s.list
+}
@@ -0,0 +1,136 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"localplane/utils/output"
+
+	"github.com/rs/zerolog"
+)
+
+var ttyFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+var ttySymbols = map[string]string{
+	output.StepOK:      "✔",
+	output.StepWarning: "⚠",
+	output.StepFailed:  "✖",
+	output.StepSkipped: "-",
+}
+
+// ttyReporter keeps one line per finished step and redraws the running
+// step with a spinner and its elapsed time. Logs are printed above the
+// running step.
+type ttyReporter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	labels  map[string]string
+	current string
+	started time.Time
+	frame   int
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+func newTTYReporter(w io.Writer) *ttyReporter {
+	r := &ttyReporter{
+		w:      w,
+		labels: map[string]string{},
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go r.loop()
+	return r
+}
+
+func (r *ttyReporter) loop() {
+	defer close(r.done)
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.mu.Lock()
+			r.frame = (r.frame + 1) % len(ttyFrames)
+			r.draw()
+			r.mu.Unlock()
+		}
+	}
+}
+
+func (r *ttyReporter) Start(name, label string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.labels[name] = label
+	r.current = label
+	r.started = time.Now()
+	r.draw()
+}
+
+func (r *ttyReporter) Finish(step output.Step) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	label := r.labels[step.Name]
+	if label == "" {
+		label = step.Name
+	}
+	r.clear()
+	r.current = ""
+	elapsed := time.Duration(step.DurationMs) * time.Millisecond
+	line := fmt.Sprintf("%s %s (%s)", ttySymbols[step.Status], label, elapsed.Round(100*time.Millisecond))
+	if step.Error != "" {
+		line += ": " + step.Error
+	}
+	fmt.Fprintln(r.w, line)
+}
+
+func (r *ttyReporter) Skip(name, label string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clear()
+	fmt.Fprintf(r.w, "%s %s (skipped)\n", ttySymbols[output.StepSkipped], label)
+	r.draw()
+}
+
+func (r *ttyReporter) LogWriter() io.Writer {
+	return zerolog.ConsoleWriter{Out: r}
+}
+
+// Write prints a log line above the running step.
+func (r *ttyReporter) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clear()
+	n, err := r.w.Write(p)
+	r.draw()
+	return n, err
+}
+
+func (r *ttyReporter) Close() {
+	close(r.stop)
+	<-r.done
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clear()
+	r.current = ""
+}
+
+// draw redraws the running step, if any. r.mu must be held.
+func (r *ttyReporter) draw() {
+	if r.current == "" {
+		return
+	}
+	elapsed := time.Since(r.started).Truncate(time.Second)
+	fmt.Fprintf(r.w, "\r\033[K%s %s (%s)", ttyFrames[r.frame], r.current, elapsed)
+}
+
+// clear erases the running step line. r.mu must be held.
+func (r *ttyReporter) clear() {
+	if r.current != "" {
+		fmt.Fprint(r.w, "\r\033[K")
+	}
+}
@@ -0,0 +1,161 @@
+package remoterepo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"localplane/config"
+	gitutil "localplane/utils/git"
+
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	// DefaultBranch is tracked when a repository doesn't set a branch.
+	DefaultBranch = "main"
+	// MirrorsDirName is the directory, under the CLI directory, holding the
+	// bare mirrors shared by all clusters.
+	MirrorsDirName = "mirrors"
+	// MirrorsMountPath is where the mirrors directory is mounted in the kind
+	// nodes and in the ArgoCD repo-server.
+	MirrorsMountPath = "/mnt/mirrors"
+)
+
+var nameRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// Repository is a remote repository with its credentials resolved.
+type Repository struct {
+	Name      string
+	URL       string
+	Branch    string
+	Path      string
+	Namespace string
+	Auth      gitutil.Auth
+	Mirror    bool
+}
+
+// credentialsFile is the format of RepositoryConfig.CredentialsFile.
+type credentialsFile struct {
+	Username      string `yaml:"username"`
+	Password      string `yaml:"password"`
+	SSHPrivateKey string `yaml:"sshPrivateKey"`
+}
+
+// Load validates the configured repositories and resolves their
+// credentials. branches overrides the configured branch per repository name.
+func Load(cfgs []config.RepositoryConfig, branches map[string]string) ([]Repository, error) {
+	seen := map[string]bool{}
+	repos := make([]Repository, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		if !nameRegexp.MatchString(cfg.Name) {
+			return nil, fmt.Errorf("repository name %q must be a lowercase DNS label", cfg.Name)
+		}
+		if seen[cfg.Name] {
+			return nil, fmt.Errorf("repository %s is configured twice", cfg.Name)
+		}
+		seen[cfg.Name] = true
+		if strings.TrimSpace(cfg.URL) == "" {
+			return nil, fmt.Errorf("repository %s has no url", cfg.Name)
+		}
+
+		repo := Repository{
+			Name:      cfg.Name,
+			URL:       cfg.URL,
+			Branch:    cfg.Branch,
+			Path:      cfg.Path,
+			Namespace: cfg.Namespace,
+			Mirror:    cfg.Mirror,
+		}
+		if b, ok := branches[cfg.Name]; ok && b != "" {
+			repo.Branch = b
+		}
+		if repo.Branch == "" {
+			repo.Branch = DefaultBranch
+		}
+		if repo.Namespace == "" {
+			repo.Namespace = cfg.Name
+		}
+		auth, err := resolveAuth(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("repository %s: %w", cfg.Name, err)
+		}
+		repo.Auth = auth
+		repos = append(repos, repo)
+	}
+	for name := range branches {
+		if !seen[name] {
+			return nil, fmt.Errorf("branch override for unknown repository %s", name)
+		}
+	}
+	return repos, nil
+}
+
+// resolveAuth reads the credentials file when configured, then lets the
+// environment variables override it.
+func resolveAuth(cfg config.RepositoryConfig) (gitutil.Auth, error) {
+	var auth gitutil.Auth
+	if cfg.CredentialsFile != "" {
+		data, err := os.ReadFile(cfg.CredentialsFile)
+		if err != nil {
+			return auth, fmt.Errorf("reading credentials file: %w", err)
+		}
+		var creds credentialsFile
+		if err := yaml.Unmarshal(data, &creds); err != nil {
+			return auth, fmt.Errorf("parsing credentials file %s: %w", cfg.CredentialsFile, err)
+		}
+		auth = gitutil.Auth{Username: creds.Username, Password: creds.Password, SSHPrivateKey: creds.SSHPrivateKey}
+	}
+
+	prefix := "LOCALPLANE_REPO_" + strings.ToUpper(strings.ReplaceAll(cfg.Name, "-", "_"))
+	usernameEnv, passwordEnv := cfg.UsernameEnv, cfg.PasswordEnv
+	if usernameEnv == "" {
+		usernameEnv = prefix + "_USERNAME"
+	}
+	if passwordEnv == "" {
+		passwordEnv = prefix + "_PASSWORD"
+	}
+	if v := os.Getenv(usernameEnv); v != "" {
+		auth.Username = v
+	}
+	if v := os.Getenv(passwordEnv); v != "" {
+		auth.Password = v
+	}
+	return auth, nil
+}
+
+// HasCredentials reports whether the remote needs credentials.
+func (r Repository) HasCredentials() bool {
+	return r.Auth.Password != "" || r.Auth.SSHPrivateKey != ""
+}
+
+// MirrorPath returns the bare mirror of the repository under base.
+func (r Repository) MirrorPath(base string) string {
+	return filepath.Join(base, MirrorsDirName, r.Name+".git")
+}
+
+// ClusterURL is the URL the GitOps engine reads: the mounted mirror when
+// mirroring, the remote otherwise.
+func (r Repository) ClusterURL() string {
+	if r.Mirror {
+		return "file://" + MirrorsMountPath + "/" + r.Name + ".git"
+	}
+	return r.URL
+}
+
+// SyncMirror creates or updates the mirror of the repository under base.
+// When the remote can't be reached but a mirror exists, the error is
+// returned along with stale set so callers can go on offline.
+func (r Repository) SyncMirror(ctx context.Context, base string) (stale bool, err error) {
+	path := r.MirrorPath(base)
+	if err := gitutil.Mirror(ctx, path, r.URL, r.Auth); err != nil {
+		if _, statErr := os.Stat(path); statErr == nil {
+			return true, err
+		}
+		return false, err
+	}
+	return false, nil
+}
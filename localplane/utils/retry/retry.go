@@ -0,0 +1,151 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"localplane/config"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultPolicy is used for the zero fields of a Policy: the retry section
+// of config.Default.
+var DefaultPolicy = FromConfig(config.Default().Retry)
+
+// Policy is an exponential backoff: the first retry waits InitialInterval,
+// each next one Multiplier times longer, up to MaxInterval. Do gives up
+// after Attempts attempts. Zero fields take their DefaultPolicy value.
+type Policy struct {
+	Attempts        int
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	Multiplier      float64
+}
+
+// FromConfig returns the Policy of the retry section of the CLI config.
+func FromConfig(cfg config.RetryConfig) Policy {
+	return Policy{
+		Attempts:        cfg.Attempts,
+		InitialInterval: cfg.InitialInterval,
+		MaxInterval:     cfg.MaxInterval,
+		Multiplier:      cfg.Multiplier,
+	}
+}
+
+// withDefaults returns p with the DefaultPolicy values of its zero fields.
+func (p Policy) withDefaults() Policy {
+	if p.Attempts <= 0 {
+		p.Attempts = DefaultPolicy.Attempts
+	}
+	if p.InitialInterval <= 0 {
+		p.InitialInterval = DefaultPolicy.InitialInterval
+	}
+	if p.MaxInterval <= 0 {
+		p.MaxInterval = DefaultPolicy.MaxInterval
+	}
+	if p.MaxInterval < p.InitialInterval {
+		p.MaxInterval = p.InitialInterval
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = DefaultPolicy.Multiplier
+	}
+	return p
+}
+
+// next returns the wait following interval.
+func (p Policy) next(interval time.Duration) time.Duration {
+	interval = time.Duration(float64(interval) * p.Multiplier)
+	if interval > p.MaxInterval {
+		return p.MaxInterval
+	}
+	return interval
+}
+
+// permanentError marks an error not worth retrying.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so that Do returns it without retrying.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Do calls fn until it succeeds, returns a Permanent error, p.Attempts
+// attempts were made or ctx is done, waiting with the backoff of p between
+// attempts. It returns the last error of fn, unwrapped from Permanent.
+func Do(ctx context.Context, p Policy, fn func() error) error {
+	p = p.withDefaults()
+	interval := p.InitialInterval
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+		if attempt >= p.Attempts || ctx.Err() != nil {
+			return err
+		}
+		log.Debug().Err(err).Int("attempt", attempt).Dur("wait", interval).Msg("retrying")
+		if !sleep(ctx, interval) {
+			return err
+		}
+		interval = p.next(interval)
+	}
+}
+
+// Until calls fn with the backoff of p, ignoring p.Attempts, until it
+// reports done, returns a Permanent error, timeout elapses or ctx is done.
+// fn returns a non-nil error to describe why it isn't done yet; the last
+// one is included in the timeout error.
+func Until(ctx context.Context, p Policy, timeout time.Duration, fn func(ctx context.Context) (bool, error)) error {
+	p = p.withDefaults()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	interval := p.InitialInterval
+	for {
+		done, err := fn(ctx)
+		if done {
+			return nil
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+		if !sleep(ctx, interval) {
+			switch {
+			case !errors.Is(ctx.Err(), context.DeadlineExceeded):
+				return context.Cause(ctx)
+			case err != nil:
+				return fmt.Errorf("not done within %s: %w", timeout, err)
+			default:
+				return fmt.Errorf("not done within %s", timeout)
+			}
+		}
+		interval = p.next(interval)
+	}
+}
+
+// sleep waits d, returning false when ctx is done first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
@@ -0,0 +1,72 @@
+package templatesource
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Installed records the template a workspace chart was installed from. It
+// is stored in a state directory along with a pristine copy of the template
+// files (`base/`), the common ancestor used to merge template upgrades with
+// the user's edits.
+type Installed struct {
+	Source      string    `yaml:"source"`
+	InstalledAt time.Time `yaml:"installedAt"`
+}
+
+const installedFile = "installed.yaml"
+
+// BaseDir returns the pristine copy of the installed template in stateDir.
+func BaseDir(stateDir string) string {
+	return filepath.Join(stateDir, "base")
+}
+
+// SaveInstalled records src as installed in stateDir, replacing the pristine
+// copy with the template files in templateDir.
+func SaveInstalled(stateDir string, src Source, templateDir string) error {
+	if err := os.MkdirAll(stateDir, 0o755); err != nil {
+		return err
+	}
+	tmpDir, err := os.MkdirTemp(stateDir, ".base.*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	staged := filepath.Join(tmpDir, "base")
+	if err := copyTree(templateDir, staged); err != nil {
+		return fmt.Errorf("copying template base: %w", err)
+	}
+	if err := os.RemoveAll(BaseDir(stateDir)); err != nil {
+		return err
+	}
+	if err := os.Rename(staged, BaseDir(stateDir)); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(Installed{Source: src.String(), InstalledAt: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(stateDir, installedFile), data, 0o644)
+}
+
+// LoadInstalled reads the template record of stateDir. It returns nil when
+// none was recorded (workspaces created before upgrades were supported).
+func LoadInstalled(stateDir string) (*Installed, error) {
+	data, err := os.ReadFile(filepath.Join(stateDir, installedFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var installed Installed
+	if err := yaml.Unmarshal(data, &installed); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", installedFile, err)
+	}
+	return &installed, nil
+}
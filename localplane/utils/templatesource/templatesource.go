@@ -0,0 +1,316 @@
+package templatesource
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"localplane/charts"
+	gitutil "localplane/utils/git"
+	"localplane/utils/github"
+	"localplane/utils/helm"
+	"localplane/utils/retry"
+)
+
+// Kinds of template sources.
+const (
+	KindBuiltin = "builtin"
+	KindGitHub  = "github"
+	KindGit     = "git"
+	KindLocal   = "local"
+	KindOCI     = "oci"
+)
+
+// Environment variables holding the credentials used to clone a template
+// from a git URL over https.
+const (
+	UsernameEnv = "LOCALPLANE_TEMPLATE_USERNAME"
+	PasswordEnv = "LOCALPLANE_TEMPLATE_PASSWORD"
+)
+
+// LocalplaneTemplate is the workspace-template chart of the localplane
+// repository, which the builtin template is a copy of.
+const LocalplaneTemplate = "github:brandonguigo/localplane//charts/workspace-template"
+
+// scpRegexp matches scp-like git URLs such as git@github.com:org/repo.git.
+var scpRegexp = regexp.MustCompile(`^[\w.-]+@[\w.-]+:`)
+
+// Source is where a workspace template is fetched from.
+//
+// Specs, as accepted by Parse:
+//
+//	builtin                                      the chart built into the CLI (default)
+//	github:<owner>/<repo>[//<path>][?ref=<ref>]  a path of a GitHub repository
+//	<git url>[//<path>][?ref=<ref>]              any git repository (https, ssh, git@host:repo, file, or git::<url>)
+//	oci://<registry>/<chart>[?version=<v>]       a Helm chart in an OCI registry
+//	<directory>                                  a local directory
+type Source struct {
+	Kind string
+	// Location is the owner/repo (github), the URL (git, oci) or the
+	// directory (local).
+	Location string
+	// Path is the template directory inside a repository.
+	Path string
+	// Ref is the branch, tag or commit of a repository, or the chart version.
+	Ref string
+}
+
+// Parse parses a template source spec. An empty spec is the builtin template.
+func Parse(spec string) (Source, error) {
+	spec = strings.TrimSpace(spec)
+	switch {
+	case spec == "" || spec == KindBuiltin:
+		return Source{Kind: KindBuiltin}, nil
+	case strings.HasPrefix(spec, "github:"):
+		location, path, ref, err := splitRepoSpec(strings.TrimPrefix(spec, "github:"), "ref")
+		if err != nil {
+			return Source{}, err
+		}
+		if parts := strings.Split(location, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return Source{}, fmt.Errorf("invalid GitHub template source %q: expected github:<owner>/<repo>[//<path>][?ref=<ref>]", spec)
+		}
+		return Source{Kind: KindGitHub, Location: location, Path: path, Ref: ref}, nil
+	case strings.HasPrefix(spec, "oci://"):
+		location, path, version, err := splitRepoSpec(spec, "version")
+		if err != nil {
+			return Source{}, err
+		}
+		if path != "" {
+			return Source{}, fmt.Errorf("invalid OCI template source %q: a chart has no sub path", spec)
+		}
+		return Source{Kind: KindOCI, Location: location, Ref: version}, nil
+	case isGitURL(spec):
+		location, path, ref, err := splitRepoSpec(strings.TrimPrefix(spec, "git::"), "ref")
+		if err != nil {
+			return Source{}, err
+		}
+		return Source{Kind: KindGit, Location: location, Path: path, Ref: ref}, nil
+	default:
+		dir := spec
+		if strings.HasPrefix(dir, "~/") {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return Source{}, err
+			}
+			dir = filepath.Join(home, dir[2:])
+		}
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return Source{}, err
+		}
+		return Source{Kind: KindLocal, Location: abs}, nil
+	}
+}
+
+// isGitURL reports whether spec is a git URL rather than a local directory.
+func isGitURL(spec string) bool {
+	if strings.HasPrefix(spec, "git::") || scpRegexp.MatchString(spec) {
+		return true
+	}
+	for _, scheme := range []string{"https://", "http://", "ssh://", "git://", "file://"} {
+		if strings.HasPrefix(spec, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitRepoSpec splits `<location>[//<path>][?<param>=<value>]`. The `//`
+// of a URL scheme isn't a path separator.
+func splitRepoSpec(spec, param string) (location, path, value string, err error) {
+	if i := strings.Index(spec, "?"); i >= 0 {
+		query, qerr := url.ParseQuery(spec[i+1:])
+		if qerr != nil {
+			return "", "", "", fmt.Errorf("invalid template source %q: %w", spec, qerr)
+		}
+		for k := range query {
+			if k != param {
+				return "", "", "", fmt.Errorf("invalid template source %q: unknown parameter %s (expected %s)", spec, k, param)
+			}
+		}
+		value = query.Get(param)
+		spec = spec[:i]
+	}
+	start := 0
+	if i := strings.Index(spec, "://"); i >= 0 {
+		start = i + 3
+	}
+	location = spec
+	if i := strings.Index(spec[start:], "//"); i >= 0 {
+		location = spec[:start+i]
+		path = strings.Trim(spec[start+i+2:], "/")
+	}
+	return location, path, value, nil
+}
+
+// WithRef returns the source at ref (a branch, tag or commit, or the chart
+// version). The builtin template becomes LocalplaneTemplate at ref; a local
+// directory has no ref.
+func (s Source) WithRef(ref string) (Source, error) {
+	switch s.Kind {
+	case KindBuiltin:
+		src, err := Parse(LocalplaneTemplate)
+		if err != nil {
+			return src, err
+		}
+		src.Ref = ref
+		return src, nil
+	case KindLocal:
+		return s, fmt.Errorf("the local template %s has no ref", s.Location)
+	default:
+		s.Ref = ref
+		return s, nil
+	}
+}
+
+// String returns the spec of the source, which Parse parses back into s. A
+// directory under the home directory is written as ~/<path>, and git:: is
+// only added to a location that isn't recognized as a git URL on its own.
+func (s Source) String() string {
+	var b strings.Builder
+	switch s.Kind {
+	case KindBuiltin:
+		return KindBuiltin
+	case KindLocal:
+		if home, err := os.UserHomeDir(); err == nil {
+			if rel, err := filepath.Rel(home, s.Location); err == nil && rel != "." && filepath.IsLocal(rel) {
+				return "~/" + filepath.ToSlash(rel)
+			}
+		}
+		return s.Location
+	case KindGitHub:
+		b.WriteString("github:")
+	case KindGit:
+		if !isGitURL(s.Location) {
+			b.WriteString("git::")
+		}
+	}
+	b.WriteString(s.Location)
+	if s.Path != "" {
+		b.WriteString("//" + s.Path)
+	}
+	if s.Ref != "" {
+		param := "ref"
+		if s.Kind == KindOCI {
+			param = "version"
+		}
+		b.WriteString("?" + param + "=" + s.Ref)
+	}
+	return b.String()
+}
+
+// Client fetches workspace templates. GitHub downloads are cached under
+// `<CacheDir>/github` when CacheDir is set.
+type Client struct {
+	CacheDir string
+	// Retry is the backoff of the GitHub requests failing with a transient
+	// error.
+	Retry retry.Policy
+}
+
+// NewClient creates a Client caching downloads under cacheDir (empty for no
+// cache).
+func NewClient(cacheDir string) *Client {
+	return &Client{CacheDir: cacheDir}
+}
+
+// Fetch writes the template of src into dest, which must not exist yet.
+func (c *Client) Fetch(ctx context.Context, src Source, dest string) error {
+	switch src.Kind {
+	case KindBuiltin:
+		return charts.WriteWorkspaceTemplate(dest)
+	case KindGitHub:
+		owner, repo, _ := strings.Cut(src.Location, "/")
+		ghClient := github.NewClient(owner, repo)
+		ghClient.Retry = c.Retry
+		if src.Ref != "" {
+			ghClient.Ref = &src.Ref
+		}
+		if c.CacheDir != "" {
+			ghClient.CacheDir = filepath.Join(c.CacheDir, "github")
+		}
+		return ghClient.DownloadPath(ctx, src.Path, dest)
+	case KindGit:
+		return c.fetchGit(ctx, src, dest)
+	case KindOCI:
+		return helm.NewClient("").PullChart(src.Location, "", src.Ref, dest)
+	case KindLocal:
+		info, err := os.Stat(src.Location)
+		if err != nil {
+			return fmt.Errorf("template directory: %w", err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("template %s is not a directory", src.Location)
+		}
+		return copyTree(src.Location, dest)
+	default:
+		return fmt.Errorf("unknown template source kind %q", src.Kind)
+	}
+}
+
+// fetchGit clones the repository next to dest and moves the template
+// directory in place.
+func (c *Client) fetchGit(ctx context.Context, src Source, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	tmpDir, err := os.MkdirTemp(filepath.Dir(dest), "."+filepath.Base(dest)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	clonePath := filepath.Join(tmpDir, "repo")
+	auth := gitutil.Auth{Username: os.Getenv(UsernameEnv), Password: os.Getenv(PasswordEnv)}
+	if err := gitutil.Clone(ctx, clonePath, src.Location, src.Ref, auth); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(filepath.Join(clonePath, ".git")); err != nil {
+		return err
+	}
+	dir := filepath.Join(clonePath, filepath.FromSlash(src.Path))
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return fmt.Errorf("path not found in %s: %s", src.Location, src.Path)
+	}
+	if err := os.Rename(dir, dest); err != nil {
+		return fmt.Errorf("moving template to %s: %w", dest, err)
+	}
+	return nil
+}
+
+// copyTree copies the regular files and directories of src into dest,
+// skipping the .git directory.
+func copyTree(src, dest string) error {
+	return filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, rel)
+		switch {
+		case d.IsDir() && d.Name() == ".git" && rel != ".":
+			return filepath.SkipDir
+		case d.IsDir():
+			return os.MkdirAll(target, 0o755)
+		case !d.Type().IsRegular():
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, info.Mode().Perm())
+	})
+}
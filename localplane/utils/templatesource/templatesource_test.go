@@ -0,0 +1,82 @@
+package templatesource
+
+import "testing"
+
+func TestParseString(t *testing.T) {
+	t.Setenv("HOME", "/home/dev")
+	tests := []struct {
+		spec string
+		want Source
+	}{
+		{"builtin", Source{Kind: KindBuiltin}},
+		{"github:brandonguigo/localplane", Source{Kind: KindGitHub, Location: "brandonguigo/localplane"}},
+		{"github:brandonguigo/localplane//charts/workspace-template?ref=v1.2.0", Source{Kind: KindGitHub, Location: "brandonguigo/localplane", Path: "charts/workspace-template", Ref: "v1.2.0"}},
+		{"https://github.com/acme/platform.git", Source{Kind: KindGit, Location: "https://github.com/acme/platform.git"}},
+		{"https://github.com/acme/platform.git//charts/tpl?ref=main", Source{Kind: KindGit, Location: "https://github.com/acme/platform.git", Path: "charts/tpl", Ref: "main"}},
+		{"ssh://git@github.com/acme/platform.git//tpl?ref=feature/x", Source{Kind: KindGit, Location: "ssh://git@github.com/acme/platform.git", Path: "tpl", Ref: "feature/x"}},
+		{"file:///srv/git/platform.git//tpl", Source{Kind: KindGit, Location: "file:///srv/git/platform.git", Path: "tpl"}},
+		{"git@github.com:acme/platform.git", Source{Kind: KindGit, Location: "git@github.com:acme/platform.git"}},
+		{"git@github.com:acme/platform.git//charts/tpl?ref=3f2a9c1", Source{Kind: KindGit, Location: "git@github.com:acme/platform.git", Path: "charts/tpl", Ref: "3f2a9c1"}},
+		{"git::/srv/git/platform.git//tpl?ref=v1", Source{Kind: KindGit, Location: "/srv/git/platform.git", Path: "tpl", Ref: "v1"}},
+		{"oci://ghcr.io/acme/charts/workspace", Source{Kind: KindOCI, Location: "oci://ghcr.io/acme/charts/workspace"}},
+		{"oci://ghcr.io/acme/charts/workspace?version=0.3.1", Source{Kind: KindOCI, Location: "oci://ghcr.io/acme/charts/workspace", Ref: "0.3.1"}},
+		{"/srv/templates/workspace", Source{Kind: KindLocal, Location: "/srv/templates/workspace"}},
+		{"~/templates/workspace", Source{Kind: KindLocal, Location: "/home/dev/templates/workspace"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			src, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.spec, err)
+			}
+			if src != tt.want {
+				t.Errorf("Parse(%q) = %+v; want %+v", tt.spec, src, tt.want)
+			}
+			if got := src.String(); got != tt.spec {
+				t.Errorf("Parse(%q).String() = %q", tt.spec, got)
+			}
+		})
+	}
+}
+
+func TestParseCanonical(t *testing.T) {
+	t.Setenv("HOME", "/home/dev")
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"", "builtin"},
+		{"  builtin  ", "builtin"},
+		{"git::https://github.com/acme/platform.git//tpl", "https://github.com/acme/platform.git//tpl"},
+		{"https://github.com/acme/platform.git//charts/tpl/", "https://github.com/acme/platform.git//charts/tpl"},
+		{"/home/dev/templates/workspace", "~/templates/workspace"},
+		{"/home/dev", "/home/dev"},
+		{"/home/devops/tpl", "/home/devops/tpl"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			src, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.spec, err)
+			}
+			if got := src.String(); got != tt.want {
+				t.Errorf("Parse(%q).String() = %q; want %q", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{
+		"github:acme",
+		"github:acme/platform/extra",
+		"github:acme/platform?version=1",
+		"https://github.com/acme/platform.git?version=1",
+		"oci://ghcr.io/acme/charts/workspace?ref=main",
+		"oci://ghcr.io/acme/charts/workspace//tpl",
+	} {
+		if src, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) = %+v; want an error", spec, src)
+		}
+	}
+}
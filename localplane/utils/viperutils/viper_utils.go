@@ -17,21 +17,3 @@ func MapFlagToEnv(cmd *cobra.Command, flagName, envName, configKey string) error
 	}
 	return nil
 }
-
-func ConfigureViperConfigFile(cfgFile string) {
-	// if --directory is set, use it to set the config file path
-	dir := viper.GetString("directory")
-	if dir != "" {
-		viper.AddConfigPath(dir)
-	}
-
-	if cfgFile != "" {
-		// Use config file from the flag.
-		viper.SetConfigFile(cfgFile)
-	} else {
-		// Search for a config file with the name "config" (without extension).
-		viper.AddConfigPath(".ab-config.yaml")
-		viper.SetConfigName("config")
-		viper.SetConfigType("yaml")
-	}
-}
\ No newline at end of file
@@ -0,0 +1,127 @@
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// MarkerFile is the file marking the root of a workspace: the directory
+// holding the clusters/, ca/, cache/ and mirrors/ of the CLI.
+const MarkerFile = ".localplane-workspace.yaml"
+
+// ErrExists is returned by Init when the directory already is a workspace.
+var ErrExists = errors.New("already a localplane workspace")
+
+// Marker is the content of MarkerFile.
+type Marker struct {
+	// Name of the workspace, the directory name by default.
+	Name string `yaml:"name"`
+}
+
+// Find returns the closest directory holding a MarkerFile, walking up from
+// dir to the filesystem root. ok is false when there is none.
+func Find(dir string) (root string, ok bool, err error) {
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return "", false, err
+	}
+	for {
+		info, err := os.Stat(filepath.Join(dir, MarkerFile))
+		if err == nil && info.Mode().IsRegular() {
+			return dir, true, nil
+		}
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", false, err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false, nil
+		}
+		dir = parent
+	}
+}
+
+// Init makes dir, created when missing, a workspace named name (the
+// directory name when empty). It returns ErrExists when dir already has a
+// MarkerFile.
+func Init(dir, name string) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, MarkerFile)
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s: %w", dir, ErrExists)
+	}
+	if name == "" {
+		name = filepath.Base(dir)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(Marker{Name: name})
+	if err != nil {
+		return err
+	}
+	header := "# localplane workspace: the clusters, CA and caches of the CLI live next to this file\n"
+	return os.WriteFile(path, append([]byte(header), data...), 0o644)
+}
+
+// Read returns the marker of the workspace at dir.
+func Read(dir string) (Marker, error) {
+	var m Marker
+	data, err := os.ReadFile(filepath.Join(dir, MarkerFile))
+	if err != nil {
+		return m, err
+	}
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return m, fmt.Errorf("parsing %s: %w", filepath.Join(dir, MarkerFile), err)
+	}
+	if m.Name == "" {
+		m.Name = filepath.Base(dir)
+	}
+	return m, nil
+}
+
+// Clusters returns the names of the clusters of the workspace at dir: the
+// directories under its clusters/.
+func Clusters(dir string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(dir, "clusters"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
+// owned are the entries of a workspace written by the CLI.
+var owned = []string{MarkerFile, "clusters", "ca", "cache", "mirrors"}
+
+// Purge removes what the CLI wrote into the workspace at dir: the
+// MarkerFile and the clusters/, ca/, cache/ and mirrors/ directories. dir
+// itself is only removed when nothing else is left in it; removed reports
+// whether it was.
+func Purge(dir string) (removed bool, err error) {
+	for _, name := range owned {
+		if err := os.RemoveAll(filepath.Join(dir, name)); err != nil {
+			return false, err
+		}
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) > 0 {
+		return false, err
+	}
+	return true, os.Remove(dir)
+}
@@ -0,0 +1,45 @@
+package xdg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// appName is the directory of localplane under the XDG base directories.
+const appName = "localplane"
+
+// DataDir returns the default directory of the clusters, CA and caches of
+// the CLI: $XDG_DATA_HOME/localplane, else ~/.local/share/localplane.
+func DataDir() (string, error) {
+	return baseDir("XDG_DATA_HOME", ".local", "share")
+}
+
+// ConfigDir returns the directory of the CLI config file:
+// $XDG_CONFIG_HOME/localplane, else ~/.config/localplane.
+func ConfigDir() (string, error) {
+	return baseDir("XDG_CONFIG_HOME", ".config")
+}
+
+// ConfigFile returns the default config file, config.yaml in ConfigDir.
+func ConfigFile() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.yaml"), nil
+}
+
+// baseDir returns the localplane directory under the base directory named
+// by env, else under the fallback path of the home directory. Relative
+// values of env are ignored, as the XDG spec requires.
+func baseDir(env string, fallback ...string) (string, error) {
+	if base := os.Getenv(env); filepath.IsAbs(base) {
+		return filepath.Join(base, appName), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("determining the home directory for $%s: %w", env, err)
+	}
+	return filepath.Join(append(append([]string{home}, fallback...), appName)...), nil
+}